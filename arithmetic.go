@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"math"
+)
+
+//How the series combined by AddSeries, SubSeries, MulSeries, DivSeries and PowSeries are
+//aligned on x.  The zero value keeps the x values in both series.
+type JoinOptions struct {
+	Mode int //One of the Join constants
+	Fill int //One of the Fill constants
+}
+
+//Adds other to the series, aligned on x as by Join
+func (ts *Series) AddSeries(other *Series, opts JoinOptions) (*Series, error) {
+	return ts.combine(other, opts, func(u, v float64) float64 { return u + v })
+}
+
+//Subtracts other from the series, e.g. the spread between two prices, aligned as by Join
+func (ts *Series) SubSeries(other *Series, opts JoinOptions) (*Series, error) {
+	return ts.combine(other, opts, func(u, v float64) float64 { return u - v })
+}
+
+//Multiplies the series by other, aligned as by Join
+func (ts *Series) MulSeries(other *Series, opts JoinOptions) (*Series, error) {
+	return ts.combine(other, opts, func(u, v float64) float64 { return u * v })
+}
+
+//Divides the series by other, e.g. the ratio of two prices, aligned as by Join.
+//Division by zero gives an infinite or NaN value.
+func (ts *Series) DivSeries(other *Series, opts JoinOptions) (*Series, error) {
+	return ts.combine(other, opts, func(u, v float64) float64 { return u / v })
+}
+
+//Raises the series to the power of other, aligned as by Join
+func (ts *Series) PowSeries(other *Series, opts JoinOptions) (*Series, error) {
+	return ts.combine(other, opts, math.Pow)
+}
+
+func (ts *Series) combine(other *Series, opts JoinOptions, op func(u, v float64) float64) (*Series, error) {
+	left, right, err := Join(ts, other, opts.Mode, opts.Fill)
+	if err != nil {
+		return nil, err
+	}
+	for i := range left.y {
+		left.y[i] = op(left.y[i], right.y[i])
+	}
+	left.UpdateStats()
+	return left, nil
+}
+
+//Creates a new series by applying a function to each y value
+func (ts *Series) Transform(f func(y float64) float64) *Series {
+	newx := make([]float64, ts.Len)
+	newy := make([]float64, ts.Len)
+	for i := range ts.x {
+		newx[i] = ts.x[i]
+		newy[i] = f(ts.y[i])
+	}
+	return NewSeriesFrom(newx, newy)
+}
+
+//Adds a constant to each y value
+func (ts *Series) AddScalar(value float64) *Series {
+	return ts.Transform(func(y float64) float64 { return y + value })
+}
+
+//Subtracts a constant from each y value
+func (ts *Series) SubScalar(value float64) *Series {
+	return ts.Transform(func(y float64) float64 { return y - value })
+}
+
+//Multiplies each y value by a constant
+func (ts *Series) MulScalar(value float64) *Series {
+	return ts.Transform(func(y float64) float64 { return y * value })
+}
+
+//Divides each y value by a constant
+func (ts *Series) DivScalar(value float64) *Series {
+	return ts.Transform(func(y float64) float64 { return y / value })
+}
+
+//Raises each y value to a power
+func (ts *Series) PowScalar(power float64) *Series {
+	return ts.Transform(func(y float64) float64 { return math.Pow(y, power) })
+}
+
+//The absolute value of each y value
+func (ts *Series) Abs() *Series {
+	return ts.Transform(math.Abs)
+}
+
+//The natural logarithm of each y value, NaN for negative values and -Inf for zero
+func (ts *Series) Log() *Series {
+	return ts.Transform(math.Log)
+}
+
+//e raised to each y value
+func (ts *Series) Exp() *Series {
+	return ts.Transform(math.Exp)
+}
+
+//Limits each y value to [lower, upper].  NaN values are left unchanged.
+//Panics if lower is greater than upper.
+func (ts *Series) Clip(lower float64, upper float64) *Series {
+	clipped, err := ts.ClipE(lower, upper)
+	if err != nil {
+		panic(err)
+	}
+	return clipped
+}
+
+//As Clip, but returns ErrInvalidArgument if lower is greater than upper or either is NaN.
+func (ts *Series) ClipE(lower float64, upper float64) (*Series, error) {
+	if !(lower <= upper) {
+		return nil, ErrInvalidArgument
+	}
+	return ts.Transform(func(y float64) float64 {
+		if y < lower {
+			return lower
+		}
+		if y > upper {
+			return upper
+		}
+		return y
+	}), nil
+}
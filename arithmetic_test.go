@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSeriesArithmetic(t *testing.T) {
+	a := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{10, 20, 30, 40})
+	b := NewSeriesFrom([]float64{2, 3, 4, 5}, []float64{2, 4, 5, 8})
+
+	cases := []struct {
+		name string
+		op   func(*Series, *Series, JoinOptions) (*Series, error)
+		want []float64
+	}{
+		{"AddSeries", (*Series).AddSeries, []float64{22, 34, 45}},
+		{"SubSeries", (*Series).SubSeries, []float64{18, 26, 35}},
+		{"MulSeries", (*Series).MulSeries, []float64{40, 120, 200}},
+		{"DivSeries", (*Series).DivSeries, []float64{10, 7.5, 8}},
+	}
+	for _, c := range cases {
+		result, err := c.op(a, b, JoinOptions{})
+		if err != nil {
+			t.Fatal(c.name, "returned", err)
+		}
+		if !sameValues(result.x, []float64{2, 3, 4}) || !sameValues(result.y, c.want) {
+			t.Error(c.name, "was", result.x, result.y, ", should be", c.want)
+		}
+	}
+
+	left := JoinOptions{Mode: JoinLeft, Fill: FillPrevious}
+	spread, _ := a.SubSeries(b, left)
+	if !sameValues(spread.y, []float64{math.NaN(), 18, 26, 35}) {
+		t.Error("Left join spread was", spread.y)
+	}
+	squared, _ := b.PowSeries(NewSeriesFrom([]float64{2, 5}, []float64{2, 2}), left)
+	//Stats are recomputed for the result
+	StatsCheck(t, squared, 64, 4, 27.25, 4)
+
+	if _, err := a.AddSeries(nil, JoinOptions{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Nil series returned", err)
+	}
+	if _, err := a.AddSeries(b, JoinOptions{Mode: -1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Invalid join mode returned", err)
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	s := NewSeriesFrom([]float64{1, 2, 3}, []float64{-2, 0, 4})
+
+	StatsCheck(t, s.AddScalar(1), 5, -1, 1.6666666666666667, 3)
+	StatsCheck(t, s.MulScalar(-2), 4, -8, -1.3333333333333333, 3)
+	StatsCheck(t, s.Abs(), 4, 0, 2, 3)
+	StatsCheck(t, s.Clip(-1, 1), 1, -1, 0, 3)
+	if _, err := s.ClipE(1, -1); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Clip with lower above upper returned", err)
+	}
+	if !sameValues(s.SubScalar(2).DivScalar(2).y, []float64{-2, -1, 1}) {
+		t.Error("SubScalar and DivScalar were", s.SubScalar(2).DivScalar(2).y)
+	}
+	if !sameValues(s.PowScalar(2).y, []float64{4, 0, 16}) {
+		t.Error("PowScalar was", s.PowScalar(2).y)
+	}
+	if y := s.Abs().Log().Exp().y; math.Abs(y[0]-2) > 1e-12 || y[1] != 0 {
+		t.Error("Log and Exp were", y)
+	}
+	//The original series is unchanged
+	if s.y[0] != -2 || s.Mean != 2.0/3 {
+		t.Error("Arithmetic modified the series", s.y)
+	}
+}
@@ -0,0 +1,169 @@
+package analytics
+
+import (
+	"math"
+)
+
+//Returns the nth derivative of the model at x, where order 0 is the model itself.
+//Polynomial, linear, exponential, logarithmic, power and spline models are differentiated
+//exactly; other models by central differences refined by Richardson extrapolation.
+//Returns ErrOutOfRange when x is outside the domain of the model.
+func Derivative(m Model, x float64, order int) (float64, error) {
+	m = unwrapModel(m)
+	if m == nil {
+		return 0, ErrNoFit
+	}
+	if order < 0 || !finite(x) {
+		return 0, ErrInvalidArgument
+	}
+	if order == 0 {
+		return checkCalculus(m.Eval(x))
+	}
+
+	switch model := m.(type) {
+	case *LinearModel:
+		return linearDerivative(model.Gradient, order), nil
+	case *LinearThroughOriginModel:
+		return linearDerivative(model.Gradient, order), nil
+	case *PolynomialModel:
+		c, center, scale := model.polynomial()
+		for i := 0; i < order; i++ {
+			c = polynomialDerivative(c)
+		}
+		return evalPolynomial(c, (x-center)/scale) / math.Pow(scale, float64(order)), nil
+	case *ExponentialModel:
+		return checkCalculus(model.A * math.Pow(model.B, float64(order)) * math.Exp(model.B*(x-model.XOffset)))
+	case *LogarithmicModel:
+		//The nth derivative of ln(u) is (-1)^(n-1) (n-1)! / u^n
+		u := x - model.XOffset
+		if !(u > 0) {
+			return 0, ErrOutOfRange
+		}
+		factorial := math.Gamma(float64(order))
+		return checkCalculus(model.B * math.Pow(-1, float64(order-1)) * factorial / math.Pow(u, float64(order)))
+	case *PowerModel:
+		//B (B - 1) ... (B - n + 1) A u^(B - n)
+		falling := model.A
+		for i := 0; i < order; i++ {
+			falling *= model.B - float64(i)
+		}
+		if falling == 0 {
+			return 0, nil
+		}
+		return checkCalculus(falling * math.Pow(x-model.XOffset, model.B-float64(order)))
+	case *SplineModel:
+		return model.Derivative(x, order), nil
+	}
+	return checkCalculus(numericalDerivative(m.Eval, x, order))
+}
+
+//Returns the integral of the model from a to b.  Polynomial, linear, exponential, logarithmic,
+//power and spline models are integrated exactly; other models by adaptive Simpson quadrature.
+//Returns ErrOutOfRange when the interval is outside the domain of the model.
+func Integral(m Model, a float64, b float64) (float64, error) {
+	m = unwrapModel(m)
+	if m == nil {
+		return 0, ErrNoFit
+	}
+	if !finite(a, b) {
+		return 0, ErrInvalidArgument
+	}
+
+	switch model := m.(type) {
+	case *LinearModel:
+		return model.Gradient*(math.Pow(b-model.XOffset, 2)-math.Pow(a-model.XOffset, 2))/2 +
+			(model.Intercept+model.YOffset)*(b-a), nil
+	case *LinearThroughOriginModel:
+		return model.Gradient * (b*b - a*a) / 2, nil
+	case *PolynomialModel:
+		c, center, scale := model.polynomial()
+		antiderivative := make([]float64, len(c)+1)
+		for j := range c {
+			antiderivative[j+1] = c[j] / float64(j+1)
+		}
+		return scale * (evalPolynomial(antiderivative, (b-center)/scale) - evalPolynomial(antiderivative, (a-center)/scale)), nil
+	case *ExponentialModel:
+		area := model.A * (b - a)
+		if model.B != 0 {
+			area = model.A / model.B * (math.Exp(model.B*(b-model.XOffset)) - math.Exp(model.B*(a-model.XOffset)))
+		}
+		return checkCalculus(area + model.YOffset*(b-a))
+	case *LogarithmicModel:
+		//∫ ln(u) du = u ln(u) - u
+		ua, ub := a-model.XOffset, b-model.XOffset
+		if !(ua > 0 && ub > 0) {
+			return 0, ErrOutOfRange
+		}
+		return checkCalculus((model.A+model.YOffset)*(b-a) + model.B*(ub*math.Log(ub)-ub-ua*math.Log(ua)+ua))
+	case *PowerModel:
+		ua, ub := a-model.XOffset, b-model.XOffset
+		area := model.A * (math.Pow(ub, model.B+1) - math.Pow(ua, model.B+1)) / (model.B + 1)
+		if model.B == -1 {
+			area = model.A * (math.Log(ub) - math.Log(ua))
+		}
+		return checkCalculus(area + model.YOffset*(b-a))
+	case *SplineModel:
+		return model.Integral(a, b), nil
+	}
+	return checkCalculus(adaptiveSimpson(m.Eval, a, b))
+}
+
+func linearDerivative(gradient float64, order int) float64 {
+	if order == 1 {
+		return gradient
+	}
+	return 0
+}
+
+//Non-finite results lie outside the domain of the model
+func checkCalculus(value float64) (float64, error) {
+	if !finite(value) {
+		return 0, ErrOutOfRange
+	}
+	return value, nil
+}
+
+//nth central difference Σ (-1)^k C(n, k) f(x + (n/2 - k)h) / h^n, with one step of
+//Richardson extrapolation to cancel the h² error term
+func numericalDerivative(f func(float64) float64, x float64, order int) float64 {
+	difference := func(h float64) float64 {
+		var sum float64
+		binomial := 1.0
+		for k := 0; k <= order; k++ {
+			sum += math.Pow(-1, float64(k)) * binomial * f(x+(float64(order)/2-float64(k))*h)
+			binomial = binomial * float64(order-k) / float64(k+1)
+		}
+		return sum / math.Pow(h, float64(order))
+	}
+	//Balance truncation against rounding error, which grows as h^-n
+	h := math.Pow(2.2e-16, 1/float64(order+4)) * math.Max(math.Abs(x), 1)
+	return (4*difference(h/2) - difference(h)) / 3
+}
+
+//Integrates f from a to b by adaptive Simpson's rule, to a tolerance of about 1e-10
+//of the integral of |f| estimated from a few samples
+func adaptiveSimpson(f func(float64) float64, a float64, b float64) float64 {
+	if a == b {
+		return 0
+	}
+	var magnitude float64
+	for i := 0; i <= 8; i++ {
+		magnitude += math.Abs(f(a+(b-a)*float64(i)/8)) * math.Abs(b-a) / 9
+	}
+	fa, fm, fb := f(a), f((a+b)/2), f(b)
+	whole := (b - a) / 6 * (fa + 4*fm + fb)
+	return simpson(f, a, b, fa, fm, fb, whole, 1e-10*math.Max(magnitude, 1e-300), 20)
+}
+
+func simpson(f func(float64) float64, a float64, b float64, fa float64, fm float64, fb float64, whole float64, tolerance float64, depth int) float64 {
+	m := (a + b) / 2
+	flm, frm := f((a+m)/2), f((m+b)/2)
+	left := (m - a) / 6 * (fa + 4*flm + fm)
+	right := (b - m) / 6 * (fm + 4*frm + fb)
+	delta := left + right - whole
+	if depth <= 0 || math.Abs(delta) <= 15*tolerance {
+		return left + right + delta/15
+	}
+	return simpson(f, a, m, fa, flm, fm, left, tolerance/2, depth-1) +
+		simpson(f, m, b, fm, frm, fb, right, tolerance/2, depth-1)
+}
@@ -0,0 +1,48 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDerivativeAndIntegral(t *testing.T) {
+	logistic := &GrowthModel{NonlinearModel: &NonlinearModel{Function: logisticFunction, Params: []float64{10, 0, 1}}}
+	cases := []struct {
+		model      Model
+		x          float64
+		derivative []float64 //Orders 1 and 2 at x
+		a, b       float64
+		integral   float64
+	}{
+		{&LinearModel{Gradient: 2, Intercept: 1, XOffset: 1, YOffset: 3}, 5, []float64{2, 0}, 0, 2, 2*(1-1)/2 + 4*2},
+		{&PolynomialModel{Coeffs: []float64{1, 0, 3}, XOffset: 1}, 3, []float64{12, 6}, 1, 3, 2 + 8},
+		{&ExponentialModel{A: 2, B: 0.5, XOffset: 0, YOffset: 1}, 2, []float64{math.E, math.E / 2}, 0, 2, 4*(math.E-1) + 2},
+		{&LogarithmicModel{A: 1, B: 2, XOffset: 0}, 2, []float64{1, -0.5}, 1, math.E, (math.E - 1) + 2},
+		{&PowerModel{A: 3, B: 2, XOffset: 0}, 2, []float64{12, 6}, 0, 2, 8},
+		{logistic, 0, []float64{2.5, 0}, -5, 5, 50},
+	}
+	for _, c := range cases {
+		for i, want := range c.derivative {
+			if d, err := Derivative(FitParameters{Model: c.model}, c.x, i+1); err != nil || math.Abs(d-want) > 1e-6 {
+				t.Error(c.model.Name(), "derivative of order", i+1, "was", d, err, ", should be", want)
+			}
+		}
+		if area, err := Integral(c.model, c.a, c.b); err != nil || math.Abs(area-c.integral) > 1e-9 {
+			t.Error(c.model.Name(), "integral was", area, err, ", should be", c.integral)
+		}
+	}
+
+	//Spline models use their own closed forms
+	spline := NewSeriesFrom([]float64{0, 1, 2}, []float64{0, 1, 4}).FitCubicSpline(SplineBoundary{})
+	if area, _ := Integral(spline, 0, 2); math.Abs(area-spline.Model.(*SplineModel).Integral(0, 2)) > 0 {
+		t.Error("Spline integral was", area)
+	}
+
+	if _, err := Integral(&LogarithmicModel{A: 1, B: 1}, -1, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Integral outside the domain returned", err)
+	}
+	if _, err := Derivative(logistic, 0, -1); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Derivative of negative order returned", err)
+	}
+}
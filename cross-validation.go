@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"math"
+)
+
+//A fitting function to be validated, e.g. (*Series).FitLinearE or a closure
+//calling FitPolynomialE with a chosen order
+type FitFunc func(*Series) (FitParameters, error)
+
+//Options for WalkForward
+type WalkForwardOptions struct {
+	//Number of points in the first training set, defaults to half the series
+	InitialTrain int
+	//Number of points predicted beyond each training set, defaults to 1
+	Horizon int
+	//Number of points the origin advances between folds, defaults to Horizon
+	Step int
+	//When positive, each training set is the last Window points before the origin (a rolling
+	//origin).  When zero, training sets grow from the start of the series (an expanding origin).
+	Window int
+}
+
+//Out-of-sample error of one fold
+type CVFold struct {
+	TrainSize int
+	TestSize  int
+	RMSE      float64
+	MAE       float64
+	Bias      float64 //Mean of actual - predicted
+}
+
+//Out-of-sample error of each fold, and aggregated over every held-out point
+type CVResult struct {
+	Folds []CVFold
+	RMSE  float64
+	MAE   float64
+	Bias  float64
+}
+
+//Estimates how well a fit generalises by k-fold cross-validation.  Points are assigned
+//to folds in rotation, so that each training set spans the series and keeps its order.
+//Returns the error of the fitting function if any fold cannot be fitted.
+func (ts *Series) CrossValidate(fit FitFunc, folds int) (*CVResult, error) {
+	if fit == nil || folds < 2 {
+		return nil, ErrInvalidArgument
+	}
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if folds > ts.Len {
+		return nil, ErrInsufficientPoints
+	}
+	result := &CVResult{}
+	for fold := 0; fold < folds; fold++ {
+		var train, test []int
+		for i := range ts.x {
+			if i%folds == fold {
+				test = append(test, i)
+			} else {
+				train = append(train, i)
+			}
+		}
+		if err := ts.validateFold(result, fit, train, test); err != nil {
+			return nil, err
+		}
+	}
+	result.aggregate()
+	return result, nil
+}
+
+//Estimates forecast error by walk-forward validation: the model is fitted to the points
+//before an origin and scored on the following Horizon points, then the origin advances.
+//Unlike k-fold cross-validation no fold is trained on points after those it predicts.
+func (ts *Series) WalkForward(fit FitFunc, opts WalkForwardOptions) (*CVResult, error) {
+	if fit == nil || opts.InitialTrain < 0 || opts.Horizon < 0 || opts.Step < 0 || opts.Window < 0 {
+		return nil, ErrInvalidArgument
+	}
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if opts.InitialTrain == 0 {
+		opts.InitialTrain = ts.Len / 2
+		if opts.Window > 0 {
+			opts.InitialTrain = opts.Window
+		}
+	}
+	if opts.Horizon == 0 {
+		opts.Horizon = 1
+	}
+	if opts.Step == 0 {
+		opts.Step = opts.Horizon
+	}
+	if opts.InitialTrain < 1 || opts.InitialTrain >= ts.Len {
+		return nil, ErrInsufficientPoints
+	}
+
+	result := &CVResult{}
+	for origin := opts.InitialTrain; origin < ts.Len; origin += opts.Step {
+		start := 0
+		if opts.Window > 0 && origin > opts.Window {
+			start = origin - opts.Window
+		}
+		train := make([]int, 0, origin-start)
+		for i := start; i < origin; i++ {
+			train = append(train, i)
+		}
+		test := []int{}
+		for i := origin; i < origin+opts.Horizon && i < ts.Len; i++ {
+			test = append(test, i)
+		}
+		if err := ts.validateFold(result, fit, train, test); err != nil {
+			return nil, err
+		}
+	}
+	result.aggregate()
+	return result, nil
+}
+
+//Fits the training points and records the errors on the test points
+func (ts *Series) validateFold(result *CVResult, fit FitFunc, train []int, test []int) error {
+	x := make([]float64, len(train))
+	y := make([]float64, len(train))
+	for j, i := range train {
+		x[j], y[j] = ts.x[i], ts.y[i]
+	}
+	params, err := fit(NewSeriesFrom(x, y))
+	if err != nil {
+		return err
+	}
+	fold := CVFold{TrainSize: len(train), TestSize: len(test)}
+	for _, i := range test {
+		residual := ts.y[i] - params.Eval(ts.x[i])
+		fold.RMSE += residual * residual
+		fold.MAE += math.Abs(residual)
+		fold.Bias += residual
+	}
+	result.Folds = append(result.Folds, fold)
+	return nil
+}
+
+//Converts the sums accumulated by validateFold into means, per fold and overall
+func (result *CVResult) aggregate() {
+	var n float64
+	for i := range result.Folds {
+		fold := &result.Folds[i]
+		result.RMSE += fold.RMSE
+		result.MAE += fold.MAE
+		result.Bias += fold.Bias
+		n += float64(fold.TestSize)
+		size := float64(fold.TestSize)
+		fold.RMSE = math.Sqrt(fold.RMSE / size)
+		fold.MAE /= size
+		fold.Bias /= size
+	}
+	result.RMSE = math.Sqrt(result.RMSE / n)
+	result.MAE /= n
+	result.Bias /= n
+}
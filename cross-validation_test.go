@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCrossValidate(t *testing.T) {
+	//A quadratic with noise: order 2 generalises better than order 1, and extrapolates better than order 8
+	x := make([]float64, 40)
+	y := make([]float64, 40)
+	for i := range x {
+		x[i] = float64(i) / 4
+		y[i] = 1 + 2*x[i] - 0.5*x[i]*x[i] + 0.3*math.Sin(float64(7*i))
+	}
+	s := NewSeriesFrom(x, y)
+	polynomial := func(order int) FitFunc {
+		return func(s *Series) (FitParameters, error) {
+			return s.FitPolynomialE(order)
+		}
+	}
+
+	scores := make([]float64, 3)
+	for _, order := range []int{1, 2} {
+		cv, err := s.CrossValidate(polynomial(order), 5)
+		if err != nil {
+			t.Fatal("CrossValidate returned", err)
+		}
+		if len(cv.Folds) != 5 || cv.Folds[0].TestSize != 8 || cv.Folds[0].TrainSize != 32 {
+			t.Error("Folds were", cv.Folds)
+		}
+		scores[order] = cv.RMSE
+	}
+	if !(scores[2] < scores[1]) || scores[2] > 0.4 {
+		t.Error("Cross-validated RMSE by order was", scores)
+	}
+
+	//Walk-forward with an expanding and a rolling origin
+	expanding, err := s.WalkForward(polynomial(2), WalkForwardOptions{InitialTrain: 20, Horizon: 5})
+	if err != nil || len(expanding.Folds) != 4 || expanding.Folds[3].TrainSize != 35 || expanding.Folds[3].TestSize != 5 {
+		t.Fatal("Expanding walk-forward returned", expanding, err)
+	}
+	overfitted, _ := s.WalkForward(polynomial(8), WalkForwardOptions{InitialTrain: 20, Horizon: 5})
+	if !(expanding.RMSE < overfitted.RMSE) {
+		t.Error("Walk-forward RMSE of order 2 was", expanding.RMSE, "and of order 8", overfitted.RMSE)
+	}
+	rolling, _ := s.WalkForward(polynomial(2), WalkForwardOptions{Window: 10, Horizon: 3, Step: 10})
+	if len(rolling.Folds) != 3 || rolling.Folds[2].TrainSize != 10 {
+		t.Error("Rolling walk-forward returned", rolling.Folds)
+	}
+
+	//An exact model has no out-of-sample error
+	line := NewSeriesFrom(x, x)
+	exact, _ := line.WalkForward((*Series).FitLinearE, WalkForwardOptions{})
+	if exact.RMSE > 1e-9 || exact.MAE > 1e-9 || math.Abs(exact.Bias) > 1e-9 || len(exact.Folds) != 20 {
+		t.Error("Exact walk-forward returned", exact)
+	}
+
+	if _, err := s.CrossValidate(polynomial(2), 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("CrossValidate with 1 fold returned", err)
+	}
+	if _, err := s.WalkForward(polynomial(2), WalkForwardOptions{InitialTrain: 40}); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("WalkForward without test points returned", err)
+	}
+	if _, err := s.CrossValidate(polynomial(50), 5); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("CrossValidate of an unfittable model returned", err)
+	}
+}
@@ -1,15 +1,16 @@
 package analytics
 
 import (
-	"fmt"
 	"math"
 )
 
+//The result of a curve fit.  Type is one of the FitType constants, the
+//embedded Model evaluates the fitted curve, and Report describes how well
+//the curve fits the source series.
 type FitParameters struct {
-	fittype int
-	values  []float64
-	xoffset float64
-	yoffset float64
+	Type int
+	Model
+	Report *FitReport
 }
 
 const (
@@ -21,6 +22,18 @@ const (
 	FitTypePolynomial
 	FitTypeGaussian
 	FitTypeParabolic
+	FitTypeCubicSpline
+	FitTypePCHIP
+	FitTypeAkima
+	FitTypeNonlinear
+	FitTypeLowess
+	FitTypeLogistic
+	FitTypeGompertz
+	FitTypeRichards
+	FitTypeSinusoid
+	FitTypeFourier
+	FitTypePeaks
+	FitTypePiecewiseLinear
 )
 
 func (ts *Series) FitExponential() (params FitParameters) {
@@ -46,12 +59,21 @@ func (ts *Series) FitExponential() (params FitParameters) {
 	B := (sum[1]*sum[4] - sum[5]*sum[3]) / denominator
 
 	params = FitParameters{
-		fittype: FitTypeExponential,
-		values:  []float64{A, B},
+		Type:  FitTypeExponential,
+		Model: &ExponentialModel{A: A, B: B, XOffset: xoffset, YOffset: yoffset},
 	}
+	params.Report = ts.GoodnessOfFit(params.Model)
 	return
 }
 
+//As FitExponential, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitExponentialE() (FitParameters, error) {
+	if err := ts.checkFit(2); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.FitExponential())
+}
+
 /**
  *              N * Σ(XY) - Σ(X)
  * intercept = ---------------------
@@ -61,44 +83,85 @@ func (ts *Series) FitExponential() (params FitParameters) {
  *
  */
 func (ts *Series) FitLinear() (params FitParameters) {
+	return ts.fitLinear(nil)
+}
+
+//As FitLinear, but each point contributes to the sums in proportion to its weight.
+//With weights of 1/σ² for per point uncertainties σ this is the maximum likelihood fit.
+func (ts *Series) FitLinearWeighted(weights []float64) FitParameters {
+	return mustFit(ts.FitLinearWeightedE(weights))
+}
+
+//As FitLinearWeighted, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitLinearWeightedE(weights []float64) (FitParameters, error) {
+	if err := ts.checkWeightedFit(2, weights); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.fitLinear(weights))
+}
+
+//Linear fit with optional weights, where N and the sums become weighted sums
+func (ts *Series) fitLinear(weights []float64) (params FitParameters) {
 	xoffset := ts.x[0] - 1
 	yoffset := ts.Min - 1
 	xdata := ts.x
 	ydata := ts.y
 	sum := []float64{0, 0, 0, 0, 0}
-	N := float64(ts.Len)
+	var N float64
 
 	for n := range xdata {
 		x := xdata[n] - xoffset
 		y := ydata[n] - yoffset
-		sum[0] += x     //Σ(X)
-		sum[1] += y     //Σ(Y)
-		sum[2] += x * x //Σ(X^2)
-		sum[3] += x * y //Σ(XY)
-		sum[4] += y * y //Σ(Y^2)
+		w := weightAt(weights, n)
+		N += w
+		sum[0] += w * x     //Σ(X)
+		sum[1] += w * y     //Σ(Y)
+		sum[2] += w * x * x //Σ(X^2)
+		sum[3] += w * x * y //Σ(XY)
+		sum[4] += w * y * y //Σ(Y^2)
 	}
 
 	var gradient = (N*sum[3] - sum[0]*sum[1]) / (N*sum[2] - sum[0]*sum[0])
 	var intercept = (sum[1] / N) - (gradient*sum[0])/N
 	var correlation = (N*sum[3] - sum[0]*sum[1]) / math.Sqrt((N*sum[2]-sum[0]*sum[0])*(N*sum[4]-sum[1]*sum[1]))
 
+	model := &LinearModel{
+		Gradient:    gradient,
+		Intercept:   intercept,
+		Correlation: correlation,
+		XOffset:     xoffset,
+		YOffset:     yoffset,
+	}
+	det := N*sum[2] - sum[0]*sum[0]
+	model.cov = [][]float64{{N / det, -sum[0] / det}, {-sum[0] / det, sum[2] / det}}
 	params = FitParameters{
-		fittype: FitTypeLinear,
-		values:  []float64{gradient, intercept, correlation},
+		Type:  FitTypeLinear,
+		Model: model,
 	}
+	params.Report = ts.GoodnessOfFitWeighted(params.Model, weights)
+	model.setResidualVariance(params.Report)
 	return
 }
 
+//As FitLinear, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitLinearE() (FitParameters, error) {
+	if err := ts.checkFit(2); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.FitLinear())
+}
+
+//Fits y = Gradient * x, a line through (0, 0).  Unlike earlier versions the gradient is
+//fitted to x and y as given; it was previously fitted to x and y shifted by offsets that
+//Extrapolate did not apply, so the line did not pass through the data.
 func (ts *Series) FitLinearThroughOrigin() (params FitParameters) {
-	xoffset := ts.x[0] - 1
-	yoffset := ts.Min - 1
 	xdata := ts.x
 	ydata := ts.y
 	sum := []float64{0, 0, 0, 0, 0}
 
 	for n := 0; n < ts.Len; n++ {
-		x := xdata[n] - xoffset
-		y := ydata[n] - yoffset
+		x := xdata[n]
+		y := ydata[n]
 		sum[0] += x * x //sumSqX
 		sum[1] += x * y //sumXY
 	}
@@ -106,39 +169,80 @@ func (ts *Series) FitLinearThroughOrigin() (params FitParameters) {
 	var gradient = sum[1] / sum[0]
 
 	params = FitParameters{
-		fittype: FitTypeLinearThroughOrigin,
-		values:  []float64{gradient},
+		Type:  FitTypeLinearThroughOrigin,
+		Model: &LinearThroughOriginModel{Gradient: gradient},
 	}
+	params.Report = ts.GoodnessOfFit(params.Model)
 	return params
 }
 
+//As FitLinearThroughOrigin, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitLinearThroughOriginE() (FitParameters, error) {
+	if err := ts.checkFit(1); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.FitLinearThroughOrigin())
+}
+
 func (ts *Series) FitLogarithmic() (params FitParameters) {
+	return ts.fitLogarithmic(nil)
+}
+
+//As FitLogarithmic, but each point contributes to the sums in proportion to its weight.
+func (ts *Series) FitLogarithmicWeighted(weights []float64) FitParameters {
+	return mustFit(ts.FitLogarithmicWeightedE(weights))
+}
+
+//As FitLogarithmicWeighted, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitLogarithmicWeightedE(weights []float64) (FitParameters, error) {
+	if err := ts.checkWeightedFit(2, weights); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.fitLogarithmic(weights))
+}
+
+func (ts *Series) fitLogarithmic(weights []float64) (params FitParameters) {
 	xoffset := ts.x[0] - 1
 	yoffset := ts.Min - 1
 	xdata := ts.x
 	ydata := ts.y
 	var sum = []float64{0, 0, 0, 0}
-	N := float64(ts.Len)
+	var N float64
 
 	for n := range ts.x {
 		x := xdata[n] - xoffset
 		y := ydata[n] - yoffset
-		sum[0] += math.Log(x)
-		sum[1] += y * math.Log(x)
-		sum[2] += y
-		sum[3] += math.Pow(math.Log(x), 2)
+		w := weightAt(weights, n)
+		N += w
+		sum[0] += w * math.Log(x)
+		sum[1] += w * y * math.Log(x)
+		sum[2] += w * y
+		sum[3] += w * math.Pow(math.Log(x), 2)
 	}
 
 	var B = (N*sum[1] - sum[2]*sum[0]) / (N*sum[3] - sum[0]*sum[0])
 	var A = (sum[2] - B*sum[0]) / N
 
+	model := &LogarithmicModel{A: A, B: B, XOffset: xoffset, YOffset: yoffset}
+	det := N*sum[3] - sum[0]*sum[0]
+	model.cov = [][]float64{{sum[3] / det, -sum[0] / det}, {-sum[0] / det, N / det}}
 	params = FitParameters{
-		fittype: FitTypeLogarithmic,
-		values:  []float64{A, B},
+		Type:  FitTypeLogarithmic,
+		Model: model,
 	}
+	params.Report = ts.GoodnessOfFitWeighted(params.Model, weights)
+	model.setResidualVariance(params.Report)
 	return
 }
 
+//As FitLogarithmic, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitLogarithmicE() (FitParameters, error) {
+	if err := ts.checkFit(2); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.FitLogarithmic())
+}
+
 func (ts *Series) FitPower() (params FitParameters) {
 	xoffset := ts.x[0] - 1
 	yoffset := ts.Min - 1
@@ -160,294 +264,269 @@ func (ts *Series) FitPower() (params FitParameters) {
 	var A = math.Pow(math.E, (sum[2]-B*sum[0])/N)
 
 	params = FitParameters{
-		fittype: FitTypePower,
-		values:  []float64{A, B},
+		Type:  FitTypePower,
+		Model: &PowerModel{A: A, B: B, XOffset: xoffset, YOffset: yoffset},
 	}
+	params.Report = ts.GoodnessOfFit(params.Model)
 	return
 }
 
+//As FitPower, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitPowerE() (FitParameters, error) {
+	if err := ts.checkFit(2); err != nil {
+		return FitParameters{}, err
+	}
+	return checkFitValues(ts.FitPower())
+}
+
+//Fits a polynomial of the given order.  The coefficients and report are NaN if there are
+//too few points, the data is not finite or the system is rank deficient; use FitPolynomialE
+//to handle these as errors.  Panics on an empty series or a negative order.
 func (ts *Series) FitPolynomial(order int) (params FitParameters) {
+	params, err := ts.FitPolynomialE(order)
+	if err == nil {
+		return
+	}
+	if ts.Len == 0 || order < 0 {
+		panic(err)
+	}
+	nan := make([]float64, order+1)
+	for i := range nan {
+		nan[i] = math.NaN()
+	}
+	params = FitParameters{
+		Type:  FitTypePolynomial,
+		Model: &PolynomialModel{Coeffs: nan, XOffset: ts.x[0] - 1, YOffset: ts.Min - 1, Condition: math.Inf(1)},
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//As FitPolynomial, but each point contributes to the sums in proportion to its weight.
+func (ts *Series) FitPolynomialWeighted(order int, weights []float64) FitParameters {
+	return mustFit(ts.FitPolynomialWeightedE(order, weights))
+}
+
+//As FitPolynomialWeighted, but returns an error for invalid input rather than panicking or returning NaN.
+func (ts *Series) FitPolynomialWeightedE(order int, weights []float64) (FitParameters, error) {
+	if order < 0 {
+		return FitParameters{}, ErrInvalidArgument
+	}
+	if err := ts.checkWeightedFit(order+1, weights); err != nil {
+		return FitParameters{}, err
+	}
+	return ts.fitPolynomialE(order, weights)
+}
+
+//Polynomial fit by QR decomposition of the Vandermonde matrix in x scaled to [-1, 1].
+//Returns ErrSingularMatrix if the system is rank deficient.
+func (ts *Series) fitPolynomialE(order int, weights []float64) (params FitParameters, err error) {
 	xoffset := ts.x[0] - 1
 	yoffset := ts.Min - 1
-	xdata := ts.x
-	ydata := ts.y
-	rhs := [][]float64{}
-	lhs := []float64{}
-	k := order + 1
-	a := float64(0)
-	var b float64 = 0
-	for i := 0; i < k; i++ {
-		for l := range ts.x {
-			a += math.Pow(xdata[l]-xoffset, float64(i)) * (ydata[l] - yoffset)
-		}
-		lhs = append(lhs, a)
-		a = 0
-		var c = []float64{}
-		for j := 0; j < k; j++ {
-			for l := range xdata {
-				b += math.Pow(xdata[l]-xoffset, float64(i+j))
-			}
-			c = append(c, b)
-			b = 0
-		}
-		rhs = append(rhs, c)
+	center := (ts.x[0] + ts.x[ts.Len-1]) / 2
+	scale := (ts.x[ts.Len-1] - ts.x[0]) / 2
+	if scale == 0 {
+		scale = 1
 	}
-	rhs = append(rhs, lhs)
 
-	equation := gaussianElimination(rhs, k)
+	design := make([][]float64, ts.Len)
+	y := make([]float64, ts.Len)
+	for l := range ts.x {
+		t := (ts.x[l] - center) / scale
+		design[l] = make([]float64, order+1)
+		design[l][0] = 1
+		for i := 1; i <= order; i++ {
+			design[l][i] = design[l][i-1] * t
+		}
+		y[l] = ts.y[l] - yoffset
+	}
+	solution, err := solveLeastSquares(design, y, weights)
+	if err != nil {
+		return
+	}
 
+	model := newScaledPolynomial(solution.coef, center, scale, xoffset, yoffset)
+	model.Condition = solution.condition
+	model.cov = solution.unscaledCovariance()
 	params = FitParameters{
-		fittype: FitTypePolynomial,
-		values:  equation,
-		xoffset: xoffset,
-		yoffset: yoffset,
+		Type:  FitTypePolynomial,
+		Model: model,
 	}
+	params.Report = ts.GoodnessOfFitWeighted(params.Model, weights)
+	model.setResidualVariance(params.Report)
 	return
 }
 
-func Extrapolate(params FitParameters, x float64) float64 {
-	switch params.fittype {
-	case FitTypePolynomial:
-		var answer float64 = 0
-		for w := 0; w < len(params.values); w++ {
-			answer += params.values[w] * math.Pow((x-params.xoffset), float64(w))
-		}
-		return answer + params.yoffset
-	case FitTypeExponential:
-		A := params.values[0]
-		B := params.values[1]
-		return A*math.Pow(math.E, B*(x-params.xoffset)) + params.yoffset
-	case FitTypeLinear:
-		gradient := params.values[0]
-		intercept := params.values[1]
-		return (x-params.xoffset)*gradient + intercept
-	case FitTypeLinearThroughOrigin:
-		Gradient := params.values[0]
-		return x * Gradient
-	case FitTypeLogarithmic:
-		A := params.values[0]
-		B := params.values[1]
-		return A + B*math.Log((x-params.xoffset)) + params.yoffset
-	case FitTypePower:
-		A := params.values[0]
-		B := params.values[1]
-		return A*math.Pow((x-params.xoffset), B) + params.yoffset
-	case FitTypeGaussian:
-		A := params.values[0]
-		B := params.values[1]
-		C := params.values[2]
-		return (A*math.Pow((x-params.xoffset), 2) + B*(x-params.xoffset) + C) + params.yoffset
-	case FitTypeParabolic:
-		Height := params.values[0]
-		Position := params.values[1]
-		Width := params.values[2]
-		return (Height * math.Exp(-1*math.Pow(((x-params.xoffset)-Position)/(0.6006*Width), 2))) + params.yoffset
-	}
-
-	panic(fmt.Errorf("No Fit Available"))
+//As FitPolynomial, but returns an error for invalid input rather than panicking or returning NaN.
+//ErrSingularMatrix is returned for rank deficient systems, e.g. too few distinct x values.
+func (ts *Series) FitPolynomialE(order int) (FitParameters, error) {
+	if order < 0 {
+		return FitParameters{}, ErrInvalidArgument
+	}
+	if err := ts.checkFit(order + 1); err != nil {
+		return FitParameters{}, err
+	}
+	return ts.fitPolynomialE(order, nil)
 }
 
+//Evaluates a fitted model at x.  Panics if no fit is available.
+func Extrapolate(m Model, x float64) float64 {
+	y, err := ExtrapolateE(m, x)
+	if err != nil {
+		panic(err)
+	}
+	return y
+}
+
+//As Extrapolate, but returns ErrNoFit rather than panicking when no fit is available.
+func ExtrapolateE(m Model, x float64) (float64, error) {
+	m = unwrapModel(m)
+	if m == nil {
+		return 0, ErrNoFit
+	}
+	return m.Eval(x), nil
+}
+
+//Fits a parabola to ln(y) and the Gaussian peak it describes.  The parabola is
+//solved by QR decomposition on scaled x, as for FitPolynomial.
 func (ts *Series) FitGaussianParabolic() (params []FitParameters) {
 	xoffset := ts.x[0] - 1
 	yoffset := ts.Min - 1
-	xdata := ts.x
-	ydata := ts.y
-	var n float64 = float64(ts.Len)
-	var sumx, sumy, sumxy, sumx2, sumx3, sumx4, sumx2y float64
+	x := make([]float64, ts.Len)
+	lny := make([]float64, ts.Len)
 	for i := range ts.x {
-		x := xdata[i] - xoffset
-		y := ydata[i] - yoffset
-		lny := math.Log(y)
-		sumx += x
-		sumy += lny
-		sumxy += x * lny
-		x2 := x * x
-		sumx2 += x2
-		sumx3 += math.Pow(x, 3)
-		sumx4 += math.Pow(x, 4)
-		sumx2y += lny * math.Pow(x, 2)
-	}
-
-	D := n*sumx2*sumx4 + 2*sumx*sumx2*sumx3 - math.Pow(sumx2, 3) - math.Pow(sumx, 2)*sumx4 - n*math.Pow(sumx3, 2)
-	a := (n*sumx2*sumx2y + sumx*sumx3*sumy + sumx*sumx2*sumxy - math.Pow(sumx2, 2)*sumy - math.Pow(sumx, 2)*sumx2y - n*sumx3*sumxy) / D
-	b := (n*sumx4*sumxy + sumx*sumx2*sumx2y + sumx2*sumx3*sumy - math.Pow(sumx2, 2)*sumxy - sumx*sumx4*sumy - n*sumx3*sumx2y) / D
-	c := (sumx2*sumx4*sumy + sumx2*sumx3*sumxy + sumx*sumx3*sumx2y - math.Pow(sumx2, 2)*sumx2y - sumx*sumx4*sumxy - math.Pow(sumx3, 2)*sumy) / D
+		x[i] = ts.x[i]
+		lny[i] = math.Log(ts.y[i] - yoffset)
+	}
+	//The log series has the same x offset, so its coefficients are in x - xoffset
+	a, b, c := math.NaN(), math.NaN(), math.NaN()
+	if fit, err := NewSeriesFrom(x, lny).fitPolynomialE(2, nil); err == nil {
+		parabola := fit.Model.(*PolynomialModel)
+		a, b, c = parabola.Coeffs[2], parabola.Coeffs[1], parabola.Coeffs[0]+parabola.YOffset
+	}
 	height := math.Exp(c - a*math.Pow(b/(2*a), 2))
 	position := -b / (2 * a)
 	width := 2.35703 / (math.Sqrt(2) * math.Sqrt(-a))
 	params = []FitParameters{
 		FitParameters{
-			fittype: FitTypeGaussian,
-			values:  []float64{a, b, c, D},
-			xoffset: xoffset,
-			yoffset: yoffset,
+			Type:  FitTypeParabolic,
+			Model: &ParabolicModel{A: a, B: b, C: c, XOffset: xoffset, YOffset: yoffset},
 		},
 		FitParameters{
-			fittype: FitTypeParabolic,
-			values:  []float64{height, position, width},
-			xoffset: xoffset,
-			yoffset: yoffset,
+			Type: FitTypeGaussian,
+			Model: &GaussianModel{
+				Height:   height,
+				Position: position,
+				Width:    width,
+				XOffset:  xoffset,
+				YOffset:  yoffset,
+			},
 		},
 	}
+	for i := range params {
+		params[i].Report = ts.GoodnessOfFit(params[i].Model)
+	}
 	return
 }
 
-func (ts *Series) FitLoess(bandwidth float64) (points *Series) {
-	xdata := ts.x
-	ydata := ts.y
-	xval := make([]float64, ts.Len)
-	yval := make([]float64, ts.Len)
-	for i := range xdata {
-		xval[i] = xdata[i]
-		yval[i] = ydata[i]
+//As FitGaussianParabolic, but returns an error for invalid input rather than panicking or returning NaN.
+//ErrNoFit is returned when the data does not describe a peak.
+func (ts *Series) FitGaussianParabolicE() ([]FitParameters, error) {
+	if err := ts.checkFit(3); err != nil {
+		return nil, err
 	}
-	var distinctX = array_unique(xval)
-
-	if 2/float64(len(distinctX)) > bandwidth {
-		bandwidth = math.Min(float64(len(distinctX)), 1)
+	params := ts.FitGaussianParabolic()
+	parabola := params[0].Model.(*ParabolicModel)
+	if !finite(parabola.Coefficients()...) {
+		return nil, ErrSingularMatrix
 	}
-
-	res := []float64{}
-
-	var left = 0
-	var right int = int(math.Floor(bandwidth*float64(len(xval))) - 1)
-
-	for i := range xval {
-		var x = xval[i]
-
-		if i > 0 {
-			if right < len(xval)-1 &&
-				xval[int(right)+1]-xval[i] < xval[i]-xval[left] {
-				left++
-				right++
-			}
-		}
-
-		var edge int
-		if xval[i]-xval[left] > xval[right]-xval[i] {
-			edge = left
-		} else {
-			edge = right
-		}
-		denom := math.Abs(1.0 / (xval[edge] - x))
-		var sumWeights float64 = 0
-		var sumX float64 = 0
-		var sumXSquared float64 = 0
-		var sumY float64 = 0
-		var sumXY float64 = 0
-
-		var k = left
-		for k <= right {
-			var xk = xval[k]
-			var yk = yval[k]
-			var dist float64
-			if k < i {
-				dist = (x - xk)
-			} else {
-				dist = (xk - x)
-			}
-			var w = tricube(dist * denom)
-			var xkw = xk * w
-			sumWeights += w
-			sumX += xkw
-			sumXSquared += xk * xkw
-			sumY += yk * w
-			sumXY += yk * xkw
-			k++
-		}
-
-		var meanX = sumX / sumWeights
-
-		var meanY = sumY / sumWeights
-		var meanXY = sumXY / sumWeights
-		var meanXSquared = sumXSquared / sumWeights
-
-		var beta float64
-		if meanXSquared == meanX*meanX {
-			beta = 0
-		} else {
-			beta = (meanXY - meanX*meanY) / (meanXSquared - meanX*meanX)
-		}
-		alpha := meanY - beta*meanX
-		res = append(res, beta*x+alpha)
+	if !(parabola.A < 0) || !finite(params[1].Coefficients()...) {
+		return nil, ErrNoFit
 	}
-	newts := NewSeriesFrom(xval, res)
-	points = newts
-	return
+	return params, nil
 }
 
+//Returns R² of predicted values, one for each point of the series in the same order.
+//GoodnessOfFit computes this and the other statistics directly from a model.
 func (ts *Series) CoefficientOfDetermination(pred *Series) float64 {
-	xdata := ts.x
-	ydata := ts.y
-	var sse, ssyy float64
-	for i := range xdata {
-		y := ydata[i]
-		ssyy += math.Pow(y-pred.y[i], 2)
-		sse += math.Pow(y-ts.Mean, 2)
+	if pred.Len != ts.Len {
+		return math.NaN()
+	}
+	var ssres, sstot float64
+	for i := range ts.y {
+		ssres += math.Pow(ts.y[i]-pred.y[i], 2)
+		sstot += math.Pow(ts.y[i]-ts.Mean, 2)
 	}
-	return 1 - (ssyy / sse)
+	return 1 - (ssres / sstot)
 }
 
-func (ts *Series) StandardError(pred [][]float64) float64 {
-	ydata := ts.y
+//Returns the standard error of predicted values from a two parameter fit such as a line,
+//with one predicted value for each point of the series in the same order.
+//GoodnessOfFit computes this for any number of parameters directly from a model.
+func (ts *Series) StandardError(pred *Series) float64 {
+	if pred.Len != ts.Len {
+		return math.NaN()
+	}
 	var SE float64 = 0
-	for i := range ydata {
-		SE += math.Pow(ydata[i]-pred[i][1], 2)
+	for i := range ts.y {
+		SE += math.Pow(ts.y[i]-pred.y[i], 2)
 	}
-	SE = math.Sqrt(SE / (float64(ts.Len) - 2))
-
-	return SE
+	return math.Sqrt(SE / (float64(ts.Len) - 2))
 }
 
-func array_unique(values []float64) []float64 {
-	o := make(map[float64]float64)
-	r := []float64{}
-	for i := range values {
-		o[values[i]] = values[i]
-	}
-	for i := range o {
-		r = append(r, o[i])
+//Returns the weight of point n, or 1 for unweighted fits
+func weightAt(weights []float64, n int) float64 {
+	if weights == nil {
+		return 1
 	}
-	return r
+	return weights[n]
 }
 
-func tricube(x float64) float64 {
-	var tmp = 1 - math.Pow(x, 3)
-	return math.Pow(tmp, 3)
+//Converts per point standard deviations into weights of 1/σ²
+func InverseVarianceWeights(sigma []float64) []float64 {
+	weights := make([]float64, len(sigma))
+	for i := range sigma {
+		weights[i] = 1 / (sigma[i] * sigma[i])
+	}
+	return weights
 }
 
-/**
- * Code extracted from https://github.com/Tom-Alexander/regression-js/
- */
-func gaussianElimination(a [][]float64, o int) []float64 {
-	x := make([]float64, o)
-	n := len(a) - 1
-	maxrow := 0
-	var tmp float64 = 0
-	for i := 0; i < n; i++ {
-		maxrow = i
-		for j := i + 1; j < n; j++ {
-			if math.Abs(a[i][j]) > math.Abs(a[i][maxrow]) {
-				maxrow = j
-			}
-		}
-		for k := i; k < n+1; k++ {
-			tmp = a[k][i]
-			a[k][i] = a[k][maxrow]
-			a[k][maxrow] = tmp
+//Validates a weighted fit.  Weights must be finite and non-negative, with at least min points
+//having a positive weight.
+func (ts *Series) checkWeightedFit(min int, weights []float64) error {
+	if err := ts.checkFit(min); err != nil {
+		return err
+	}
+	if len(weights) != ts.Len {
+		return ErrInvalidArgument
+	}
+	positive := 0
+	for _, w := range weights {
+		if w < 0 || !finite(w) {
+			return ErrInvalidArgument
 		}
-		for j := i + 1; j < n; j++ {
-			for k := n; k >= i; k-- {
-				a[k][j] -= a[k][i] * a[i][j] / a[i][i]
-			}
+		if w > 0 {
+			positive++
 		}
 	}
-	for j := n - 1; j >= 0; j-- {
-		tmp = 0
-		for k := j + 1; k < n; k++ {
-			tmp += a[k][j] * x[k]
-		}
-		x[j] = (a[n][j] - tmp) / a[j][j]
+	if positive < min {
+		return ErrInsufficientPoints
+	}
+	return nil
+}
+
+//Panics if a fit returned an error
+func mustFit(params FitParameters, err error) FitParameters {
+	if err != nil {
+		panic(err)
+	}
+	return params
+}
+
+//Checks that the fitted coefficients are finite
+func checkFitValues(params FitParameters) (FitParameters, error) {
+	if !finite(params.Coefficients()...) {
+		return FitParameters{}, ErrSingularMatrix
 	}
-	return x
+	return params, nil
 }
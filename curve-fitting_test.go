@@ -0,0 +1,258 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitErrorVariants(t *testing.T) {
+	if _, err := NewSeries().FitLinearE(); !errors.Is(err, ErrEmptySeries) {
+		t.Error("FitLinearE returned", err, ", should be", ErrEmptySeries)
+	}
+
+	short := NewSeriesFrom([]float64{1, 2}, []float64{1, 2})
+	if _, err := short.FitPolynomialE(2); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("FitPolynomialE returned", err, ", should be", ErrInsufficientPoints)
+	}
+
+	flat := NewSeriesFrom([]float64{1, 1, 1}, []float64{1, 2, 3})
+	if _, err := flat.FitLinearE(); !errors.Is(err, ErrSingularMatrix) {
+		t.Error("FitLinearE returned", err, ", should be", ErrSingularMatrix)
+	}
+
+	if _, err := ExtrapolateE(FitParameters{}, 1); !errors.Is(err, ErrNoFit) {
+		t.Error("ExtrapolateE returned", err, ", should be", ErrNoFit)
+	}
+}
+
+type constantModel float64
+
+func (m constantModel) Eval(x float64) float64  { return float64(m) }
+func (m constantModel) Coefficients() []float64 { return []float64{float64(m)} }
+func (m constantModel) Name() string            { return "constant" }
+func (m constantModel) String() string          { return "y = c" }
+
+func TestFitModels(t *testing.T) {
+	x, y := testdata[0][0], testdata[0][1]
+	s := NewSeriesFrom(x, y)
+
+	fits := []FitParameters{s.FitLinear(), s.FitPolynomial(1), s.FitPolynomial(2)}
+	for _, fit := range fits {
+		if v := Extrapolate(fit, 6); math.Abs(v-11) > 1e-9 {
+			t.Error(fit.Name(), "extrapolated to", v, ", should be 11")
+		}
+	}
+
+	linear := fits[0].Model.(*LinearModel)
+	if math.Abs(linear.Gradient-2) > 1e-9 {
+		t.Error("Linear gradient was", linear.Gradient, ", should be 2")
+	}
+
+	if v := Extrapolate(constantModel(3), 10); v != 3 {
+		t.Error("Custom model extrapolated to", v, ", should be 3")
+	}
+
+	//The line through the origin is fitted without offsets, so it reproduces y = 3x
+	origin := NewSeriesFrom([]float64{10, 11, 12, 13}, []float64{30, 33, 36, 39}).FitLinearThroughOrigin()
+	if v := Extrapolate(origin, 20); math.Abs(v-60) > 1e-9 {
+		t.Error("Line through the origin extrapolated to", v, ", should be 60")
+	}
+
+	//The parabola in ln(y) is evaluated as the Gaussian it describes
+	parabola := &ParabolicModel{A: -1, B: 0, C: math.Log(4), XOffset: 2, YOffset: 1}
+	if v, g := parabola.Eval(3), 4*math.Exp(-1)+1; math.Abs(v-g) > 1e-12 || parabola.LogEval(2) != math.Log(4) {
+		t.Error("Parabolic model evaluated to", v, ", should be", g)
+	}
+
+	//Nil models are reported as missing fits
+	var missing *LinearModel
+	if _, err := ExtrapolateE(missing, 1); !errors.Is(err, ErrNoFit) {
+		t.Error("ExtrapolateE of a nil model returned", err)
+	}
+	if _, err := ExtrapolateE(FitParameters{Model: missing}, 1); !errors.Is(err, ErrNoFit) {
+		t.Error("ExtrapolateE of a nil wrapped model returned", err)
+	}
+}
+
+func TestFitReport(t *testing.T) {
+	s := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{1, 3, 2, 4})
+	report := s.FitLinear().Report
+
+	//y = 0.8x + 0.5, residuals -0.3, 0.9, -0.9, 0.3
+	if report.DegreesOfFreedom != 2 {
+		t.Error("Degrees of freedom was", report.DegreesOfFreedom, ", should be 2")
+	}
+	if math.Abs(report.RSquared-0.64) > 1e-9 {
+		t.Error("R² was", report.RSquared, ", should be 0.64")
+	}
+	if math.Abs(report.AdjustedRSquared-0.46) > 1e-9 {
+		t.Error("Adjusted R² was", report.AdjustedRSquared, ", should be 0.46")
+	}
+	if math.Abs(report.MAE-0.6) > 1e-9 {
+		t.Error("MAE was", report.MAE, ", should be 0.6")
+	}
+	if math.Abs(report.StandardError-math.Sqrt(0.9)) > 1e-9 {
+		t.Error("Standard error was", report.StandardError, ", should be", math.Sqrt(0.9))
+	}
+	if _, r := report.Residuals.Point(1); math.Abs(r-0.9) > 1e-9 {
+		t.Error("Second residual was", r, ", should be 0.9")
+	}
+
+	//The series methods take predicted values as a series
+	pred := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{1.3, 2.1, 2.9, 3.7})
+	if r2 := s.CoefficientOfDetermination(pred); math.Abs(r2-0.64) > 1e-9 {
+		t.Error("CoefficientOfDetermination was", r2, ", should be 0.64")
+	}
+	if se := s.StandardError(pred); math.Abs(se-math.Sqrt(0.9)) > 1e-9 {
+		t.Error("StandardError was", se, ", should be", math.Sqrt(0.9))
+	}
+
+	//Nonparametric models report their own number of parameters
+	x := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	y := []float64{0, 1.2, 1.8, 3.1, 4.2, 4.8, 6.1, 7.2, 7.9, 9.1}
+	curve := NewSeriesFrom(x, y)
+	if spline := curve.FitCubicSpline(SplineBoundary{}).Report; spline.DegreesOfFreedom != 0 {
+		t.Error("Spline degrees of freedom was", spline.DegreesOfFreedom, ", should be 0")
+	}
+	lowess := curve.FitLowess(LowessOptions{Bandwidth: 0.8, Degree: 1}).Report
+	if !(lowess.DegreesOfFreedom > 0 && lowess.DegreesOfFreedom < 8) || !finite(lowess.StandardError, lowess.AdjustedRSquared) {
+		t.Error("LOWESS report had", lowess.DegreesOfFreedom, "degrees of freedom and standard error", lowess.StandardError)
+	}
+
+	//Custom models without Parameters count their coefficients, and a missing model gives NaN
+	if custom := s.GoodnessOfFit(constantModel(2.5)); custom.NumParameters != 1 {
+		t.Error("Custom model had", custom.NumParameters, "parameters, should be 1")
+	}
+	if missing := s.GoodnessOfFit(FitParameters{}); !math.IsNaN(missing.RSquared) {
+		t.Error("Missing model R² was", missing.RSquared)
+	}
+	if missing := s.GoodnessOfFitWeighted(nil, nil); !math.IsNaN(missing.SSE) {
+		t.Error("Nil model SSE was", missing.SSE)
+	}
+
+	//An exact fit has a finite information criterion
+	if exact := curve.FitCubicSpline(SplineBoundary{}).Report; !finite(exact.AIC, exact.BIC) {
+		t.Error("Exact fit AIC was", exact.AIC)
+	}
+}
+
+func TestFitBest(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	y := make([]float64, len(x))
+	for i := range x {
+		y[i] = 3*x[i]*x[i] - 2*x[i] + 1
+	}
+	s := NewSeriesFrom(x, y)
+
+	for _, criterion := range []int{CriterionAIC, CriterionBIC, CriterionCV} {
+		ranked, err := s.FitBestE(FitBestOptions{Criterion: criterion, MaxPolynomialOrder: 3})
+		if err != nil {
+			t.Fatal("FitBestE returned", err)
+		}
+		if ranked[0].Type != FitTypePolynomial {
+			t.Error("Criterion", criterion, "ranked", ranked[0].Name(), "first, should be polynomial")
+		}
+		for i := 1; i < len(ranked); i++ {
+			if ranked[i].Score < ranked[i-1].Score {
+				t.Error("Fits were not ranked in order of score")
+			}
+		}
+	}
+
+	//Polynomials are tried by default, and exact fits have finite scores
+	ranked, err := s.FitBestE(FitBestOptions{})
+	if err != nil || ranked[0].Type != FitTypePolynomial {
+		t.Fatal("FitBestE with default options returned", ranked, err)
+	}
+	for _, fit := range ranked {
+		if !finite(fit.Score) {
+			t.Error(fit.Name(), "scored", fit.Score)
+		}
+		//The parabola in ln(y) duplicates the Gaussian
+		if fit.Type == FitTypeParabolic {
+			t.Error("FitBest ranked the parabolic fit")
+		}
+	}
+}
+
+func TestWeightedFits(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{3, 5, 7, 30, 11}
+	s := NewSeriesFrom(x, y)
+	weights := []float64{1, 1, 1, 0, 1}
+
+	linear := s.FitLinearWeighted(weights)
+	if v := Extrapolate(linear, 4); math.Abs(v-9) > 1e-9 {
+		t.Error("Weighted linear fit extrapolated to", v, ", should be 9")
+	}
+	if r := linear.Report.RSquared; math.Abs(r-1) > 1e-9 {
+		t.Error("Weighted R² was", r, ", should be 1")
+	}
+
+	poly := s.FitPolynomialWeighted(2, weights)
+	if v := Extrapolate(poly, 4); math.Abs(v-9) > 1e-6 {
+		t.Error("Weighted polynomial fit extrapolated to", v, ", should be 9")
+	}
+
+	//Doubling a weight is equivalent to repeating the point
+	repeated := NewSeriesFrom([]float64{1, 2, 2, 3}, []float64{1, 4, 4, 2}).FitLogarithmic()
+	doubled := NewSeriesFrom([]float64{1, 2, 3}, []float64{1, 4, 2}).FitLogarithmicWeighted([]float64{1, 2, 1})
+	for i, c := range repeated.Coefficients() {
+		if math.Abs(c-doubled.Coefficients()[i]) > 1e-9 {
+			t.Error("Weighted logarithmic coefficient", i, "was", doubled.Coefficients()[i], ", should be", c)
+		}
+	}
+
+	if _, err := s.FitLinearWeightedE(weights[:3]); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("FitLinearWeightedE returned", err, ", should be", ErrInvalidArgument)
+	}
+}
+
+func TestFitPolynomialConditioning(t *testing.T) {
+	//Hourly unix timestamps, which overflow the normal equations at high order
+	poly := func(h float64) float64 {
+		return 5 - 2*h + 0.3*h*h - 0.01*math.Pow(h, 3) + 1e-4*math.Pow(h, 4)
+	}
+	x := make([]float64, 48)
+	y := make([]float64, 48)
+	for i := range x {
+		x[i] = 1.7e9 + float64(i)*3600
+		y[i] = poly(float64(i))
+	}
+	s := NewSeriesFrom(x, y)
+
+	fit, err := s.FitPolynomialE(6)
+	if err != nil {
+		t.Fatal("FitPolynomialE returned", err)
+	}
+	for _, h := range []float64{0.5, 20.25, 46.5} {
+		if v, want := Extrapolate(fit, 1.7e9+h*3600), poly(h); math.Abs(v-want) > 1e-6 {
+			t.Error("Polynomial evaluated to", v, "at hour", h, ", should be", want)
+		}
+	}
+	if c := fit.Model.(*PolynomialModel).Condition; !(c > 1 && c < 1e4) {
+		t.Error("Condition number was", c)
+	}
+
+	//Edited coefficients take effect, and the fitted covariance no longer applies
+	model := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}).FitPolynomial(1).Model.(*PolynomialModel)
+	model.Coeffs[1] = 0
+	if v, want := model.Eval(3), model.Coeffs[0]+model.YOffset; math.Abs(v-want) > 1e-12 {
+		t.Error("Edited polynomial evaluated to", v, ", should be", want)
+	}
+	if _, _, err := ConfidenceInterval(model, 3, 0.95); err == nil {
+		t.Error("ConfidenceInterval of an edited polynomial succeeded")
+	}
+
+	repeated := NewSeriesFrom([]float64{1, 1, 2, 2, 3, 3}, []float64{1, 2, 3, 4, 5, 6})
+	if _, err := repeated.FitPolynomialE(3); !errors.Is(err, ErrSingularMatrix) {
+		t.Error("FitPolynomialE returned", err, ", should be", ErrSingularMatrix)
+	}
+	if c := repeated.FitPolynomial(3).Coefficients(); !math.IsNaN(c[0]) {
+		t.Error("FitPolynomial of a rank deficient system returned", c)
+	}
+	if c := repeated.FitPolynomial(6).Coefficients(); len(c) != 7 || !math.IsNaN(c[0]) {
+		t.Error("FitPolynomial of too few points returned", c)
+	}
+}
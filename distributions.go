@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"math"
+)
+
+//Cumulative distribution function of Student's t distribution with dof degrees of freedom
+func studentTCDF(t float64, dof float64) float64 {
+	if math.IsInf(t, 0) {
+		if t > 0 {
+			return 1
+		}
+		return 0
+	}
+	tail := 0.5 * regularizedIncompleteBeta(dof/(dof+t*t), dof/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+//Inverse of studentTCDF, found by bisection
+func studentTQuantile(p float64, dof float64) float64 {
+	if !(p > 0 && p < 1) || !(dof > 0) {
+		return math.NaN()
+	}
+	if p < 0.5 {
+		return -studentTQuantile(1-p, dof)
+	}
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, dof) < p {
+		lo, hi = hi, hi*2
+		if hi > 1e300 {
+			return math.Inf(1)
+		}
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, dof) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+//Two sided p-value of a t statistic
+func studentTPValue(t float64, dof float64) float64 {
+	return 2 * studentTCDF(-math.Abs(t), dof)
+}
+
+//The regularized incomplete beta function I_x(a, b)
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	//The continued fraction converges quickly for x < (a + 1) / (a + b + 2)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+//Continued fraction for the incomplete beta function by the modified Lentz method
+func betaContinuedFraction(x float64, a float64, b float64) float64 {
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		for step := 0; step < 2; step++ {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+			numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
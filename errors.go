@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+)
+
+//Errors returned by the error-returning (E suffixed) variants of the series
+//and curve fitting functions.  Compare against them with errors.Is.
+var (
+	ErrEmptySeries        = errors.New("Series is empty")
+	ErrInsufficientPoints = errors.New("Insufficient points in series")
+	ErrNonMonotonicX      = errors.New("X values are not in non-decreasing order")
+	ErrNonFinite          = errors.New("Series contains NaN or infinite values")
+	ErrSingularMatrix     = errors.New("Matrix is singular")
+	ErrOutOfRange         = errors.New("Index out of range")
+	ErrInvalidPeriod      = errors.New("Invalid period")
+	ErrInvalidArgument    = errors.New("Invalid argument")
+	ErrNoFit              = errors.New("No Fit Available")
+	ErrNoCovariance       = errors.New("Model has no parameter covariance")
+)
+
+//Checks that x is non-decreasing
+func (ts *Series) monotonic() bool {
+	for i := 1; i < len(ts.x); i++ {
+		if ts.x[i] < ts.x[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+//Checks that x is strictly increasing
+func (ts *Series) strictlyIncreasing() bool {
+	for i := 1; i < len(ts.x); i++ {
+		if !(ts.x[i] > ts.x[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+//Validates a series prior to fitting, requiring at least min points
+func (ts *Series) checkFit(min int) error {
+	if ts.Len == 0 {
+		return ErrEmptySeries
+	}
+	if ts.Len < min {
+		return ErrInsufficientPoints
+	}
+	for i := range ts.y {
+		if !finite(ts.x[i], ts.y[i]) {
+			return ErrNonFinite
+		}
+	}
+	if !ts.monotonic() {
+		return ErrNonMonotonicX
+	}
+	return nil
+}
+
+//Reports whether all of the values are neither NaN nor infinite
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
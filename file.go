@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 )
 
+//Writes the series as lines of x and y values.  x is written in full, without truncation.
 func (ts *Series) SavePlot(path string, name string) {
 	seriestxt := make([]string, ts.Len)
 	for i := range seriestxt {
-		seriestxt[i] = fmt.Sprint(int64(ts.x[i]), ts.y[i])
+		seriestxt[i] = fmt.Sprint(strconv.FormatFloat(ts.x[i], 'f', -1, 64), " ", ts.y[i])
 	}
 	writeLines(seriestxt, path+"/"+name)
 }
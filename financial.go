@@ -1,29 +1,49 @@
 package analytics
 
+//Commodity Channel Index of the last numberOfPeriods periods of a tick series, each period
+//aggregated to a bar.  Each value is at the last tick of its period.  The result is empty
+//for fewer than 3 periods, and 0 if the typical price does not vary.  For bars built with
+//NewTimeBars, NewTickBars, NewVolumeBars or NewDollarBars use OHLCV.CommodityChannelIndex.
 func (ts *Series) CommonChannelIndex(periodLength float64, numberOfPeriods int) *Series {
 	var constant float64 = 0.015
-	j := ts.MapReduce(
-		func(t *Series) (x float64, y float64) {
-			x = ts.x[ts.Len-1]
-			y = (t.Max + t.Min + t.y[t.Len-1]) / 3
-			return
-		},
-		func(xdata []float64, ydata []float64) *Series {
-			dataseries := NewSeriesFrom(xdata, ydata)
-			if dataseries.Len < 3 {
-				return dataseries
-			}
-			sma := dataseries.Ma(3)
-			meandev := dataseries.MeanDev()
-			ny := make([]float64, len(ydata))
-			nx := make([]float64, len(ydata))
-			copy(nx, ts.x)
-			for i := range ydata {
-				ny[i] = (ydata[i] - sma.y[i]) / (constant * meandev)
-			}
-			s := NewSeriesFrom(nx, ny)
-			return s
-		},
-		periodLength, numberOfPeriods)
-	return j
+	if ts.Len == 0 {
+		return NewSeries()
+	}
+	tp := ts.periodBars(periodLength, numberOfPeriods).TypicalPrice()
+	if tp.Len < 3 {
+		return NewSeries()
+	}
+	sma := tp.Ma(3)
+	meandev := tp.MeanDev()
+	ny := make([]float64, tp.Len)
+	for i := range tp.y {
+		if meandev != 0 {
+			ny[i] = (tp.y[i] - sma.y[i]) / (constant * meandev)
+		}
+	}
+	return NewSeriesFrom(tp.x, ny)
+}
+
+//Aggregates each of the last numberOfPeriods periods into a bar with a volume of one per
+//tick.  The periods are those MapReduce uses: each covers (start, start + periodLength],
+//the last ends at the last point, and periods without points are skipped.
+func (ts *Series) periodBars(periodLength float64, numberOfPeriods int) *OHLCV {
+	bars := &OHLCV{}
+	start := ts.x[ts.Len-1] - periodLength*float64(numberOfPeriods)
+	for p := 0; p < numberOfPeriods; p++ {
+		pos, end := ts.SearchX(start), ts.SearchX(start+periodLength)
+		start += periodLength
+		//SearchX returns -1 for a value before the first point
+		if pos == -1 {
+			pos = 0
+		}
+		if end == -1 {
+			end = 0
+		}
+		if pos >= end {
+			continue
+		}
+		bars.Bars = append(bars.Bars, newBar(ts.x[pos:end], ts.y[pos:end], nil))
+	}
+	return bars
 }
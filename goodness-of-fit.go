@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"math"
+)
+
+//Goodness of fit statistics for a model, measured against the series it was fitted to.
+type FitReport struct {
+	N                int     //Number of points
+	NumParameters    float64 //Number of parameters estimated, see Model
+	DegreesOfFreedom float64 //N - NumParameters
+	RSquared         float64 //Coefficient of determination
+	AdjustedRSquared float64 //R² adjusted for the number of parameters
+	RMSE             float64 //Root mean squared error
+	MAE              float64 //Mean absolute error
+	StandardError    float64 //Standard error of the regression, √(SSE / DegreesOfFreedom)
+	AIC              float64 //Akaike information criterion
+	BIC              float64 //Bayesian information criterion
+	SSE              float64 //Sum of (weighted) squared residuals
+	Residuals        *Series //Observed minus fitted values at each x
+}
+
+//Measures how well a model describes the series.
+//The number of parameters is taken from the model's Parameters method, or else its number
+//of Coefficients.  The statistics are NaN if there is no model.
+func (ts *Series) GoodnessOfFit(m Model) *FitReport {
+	return ts.GoodnessOfFitWeighted(m, nil)
+}
+
+//As GoodnessOfFit, but the squared and absolute errors of each point are weighted.
+//RMSE and MAE are weighted means, and the residuals are left unweighted.
+//A nil weights slice weights each point equally.
+func (ts *Series) GoodnessOfFitWeighted(m Model, weights []float64) *FitReport {
+	m = unwrapModel(m)
+	if m == nil {
+		nan := math.NaN()
+		return &FitReport{
+			N:                ts.Len,
+			NumParameters:    nan,
+			DegreesOfFreedom: nan,
+			RSquared:         nan,
+			AdjustedRSquared: nan,
+			RMSE:             nan,
+			MAE:              nan,
+			StandardError:    nan,
+			AIC:              nan,
+			BIC:              nan,
+			SSE:              nan,
+		}
+	}
+	report := &FitReport{
+		N:             ts.Len,
+		NumParameters: parameterCount(m),
+	}
+	report.DegreesOfFreedom = float64(report.N) - report.NumParameters
+
+	var sumw, mean float64
+	for i := range ts.y {
+		w := weightAt(weights, i)
+		sumw += w
+		mean += w * ts.y[i]
+	}
+	mean /= sumw
+
+	rx := make([]float64, ts.Len)
+	ry := make([]float64, ts.Len)
+	var sse, sst, sae float64
+	for i := range ts.y {
+		w := weightAt(weights, i)
+		rx[i] = ts.x[i]
+		ry[i] = ts.y[i] - m.Eval(ts.x[i])
+		sse += w * ry[i] * ry[i]
+		sae += w * math.Abs(ry[i])
+		sst += w * math.Pow(ts.y[i]-mean, 2)
+	}
+	report.Residuals = NewSeriesFrom(rx, ry)
+	report.SSE = sse
+
+	n := float64(report.N)
+	k := report.NumParameters
+	report.RSquared = 1 - sse/sst
+	report.RMSE = math.Sqrt(sse / sumw)
+	report.MAE = sae / sumw
+	if report.DegreesOfFreedom > 0 {
+		report.AdjustedRSquared = 1 - (1-report.RSquared)*(n-1)/(n-k)
+		report.StandardError = math.Sqrt(sse / (n - k))
+	} else {
+		report.AdjustedRSquared = math.NaN()
+		report.StandardError = math.NaN()
+	}
+	//An exact fit would score -Inf, so the SSE is floored relative to the total variation
+	logLikelihood := n * math.Log(math.Max(sse, math.Max(1e-12*sst, math.SmallestNonzeroFloat64))/n)
+	report.AIC = logLikelihood + 2*k
+	report.BIC = logLikelihood + k*math.Log(n)
+	return report
+}
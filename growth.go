@@ -0,0 +1,180 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+)
+
+//A saturating growth curve fitted by nonlinear least squares.
+//
+//	Logistic: y = Capacity / (1 + e^(-Rate(x - Midpoint)))
+//	Gompertz: y = Capacity * e^(-e^(-Rate(x - Midpoint)))
+//	Richards: y = Capacity / (1 + Shape * e^(-Rate(x - Midpoint)))^(1/Shape)
+//
+//The midpoint is the point of inflection for the logistic and Gompertz curves.
+//The embedded NonlinearModel holds the convergence diagnostics and covariance, and its
+//Params, which Eval uses, are capacity, midpoint, rate and, for Richards curves, shape.
+type GrowthModel struct {
+	*NonlinearModel
+	name string
+}
+
+func (m *GrowthModel) Capacity() float64 {
+	return m.Params[0]
+}
+
+func (m *GrowthModel) Midpoint() float64 {
+	return m.Params[1]
+}
+
+func (m *GrowthModel) Rate() float64 {
+	return m.Params[2]
+}
+
+//The Richards shape parameter, or the limits 1 for logistic and 0 for Gompertz curves
+func (m *GrowthModel) Shape() float64 {
+	switch m.name {
+	case "richards":
+		return m.Params[3]
+	case "logistic":
+		return 1
+	}
+	return 0
+}
+
+func logisticFunction(x float64, p []float64) float64 {
+	return p[0] / (1 + math.Exp(-p[2]*(x-p[1])))
+}
+
+func gompertzFunction(x float64, p []float64) float64 {
+	return p[0] * math.Exp(-math.Exp(-p[2]*(x-p[1])))
+}
+
+func richardsFunction(x float64, p []float64) float64 {
+	return p[0] / math.Pow(1+p[3]*math.Exp(-p[2]*(x-p[1])), 1/p[3])
+}
+
+//Fits a logistic curve, with initial guesses from the linearised logit of the data.
+func (ts *Series) FitLogistic() FitParameters {
+	return mustFit(ts.FitLogisticE())
+}
+
+//As FitLogistic, but returns an error for invalid input.
+//A fit that fails to converge is not an error; check Converged on the returned model.
+func (ts *Series) FitLogisticE() (FitParameters, error) {
+	return ts.fitGrowth(FitTypeLogistic)
+}
+
+//Fits a Gompertz curve, which approaches its capacity more slowly than it leaves zero.
+func (ts *Series) FitGompertz() FitParameters {
+	return mustFit(ts.FitGompertzE())
+}
+
+//As FitGompertz, but returns an error for invalid input.
+func (ts *Series) FitGompertzE() (FitParameters, error) {
+	return ts.fitGrowth(FitTypeGompertz)
+}
+
+//Fits a Richards (generalised logistic) curve, whose shape parameter controls
+//the asymmetry of the growth.  A shape of 1 is the logistic curve.
+func (ts *Series) FitRichards() FitParameters {
+	return mustFit(ts.FitRichardsE())
+}
+
+//As FitRichards, but returns an error for invalid input.
+func (ts *Series) FitRichardsE() (FitParameters, error) {
+	return ts.fitGrowth(FitTypeRichards)
+}
+
+func (ts *Series) fitGrowth(fitType int) (params FitParameters, err error) {
+	min := 3
+	if fitType == FitTypeRichards {
+		min = 4
+	}
+	if err = ts.checkFit(min); err != nil {
+		return
+	}
+
+	var f NonlinearFunc
+	var name string
+	opts := NonlinearOptions{MaxIterations: 500}
+	linearise := func(y float64, capacity float64) float64 {
+		return math.Log(y / (capacity - y))
+	}
+	switch fitType {
+	case FitTypeLogistic:
+		f, name = logisticFunction, "logistic"
+	case FitTypeGompertz:
+		f, name = gompertzFunction, "gompertz"
+		linearise = func(y float64, capacity float64) float64 {
+			return -math.Log(-math.Log(y / capacity))
+		}
+	case FitTypeRichards:
+		f, name = richardsFunction, "richards"
+		inf := math.Inf(1)
+		opts.Lower = []float64{-inf, -inf, -inf, 1e-6}
+		opts.Upper = []float64{inf, inf, inf, inf}
+	}
+
+	initial := ts.growthGuess(linearise)
+	if fitType == FitTypeRichards {
+		initial = append(initial, 1)
+	}
+	nonlinear, err := ts.levenbergMarquardt(f, initial, opts)
+	if err != nil {
+		return
+	}
+	model := &GrowthModel{
+		NonlinearModel: nonlinear,
+		name:           name,
+	}
+	params = FitParameters{
+		Type:  fitType,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Guesses capacity, midpoint and rate.  The capacity is set just beyond the data,
+//and the midpoint and rate come from a line fitted to the linearised curve.
+func (ts *Series) growthGuess(linearise func(y float64, capacity float64) float64) []float64 {
+	first, last := ts.x[0], ts.x[ts.Len-1]
+	capacity := ts.Max + 0.05*(ts.Max-ts.Min)
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	var n, sumx, sumz, sumxx, sumxz float64
+	for i := range ts.x {
+		if !(ts.y[i] > 0 && ts.y[i] < capacity) {
+			continue
+		}
+		z := linearise(ts.y[i], capacity)
+		n++
+		sumx += ts.x[i]
+		sumz += z
+		sumxx += ts.x[i] * ts.x[i]
+		sumxz += ts.x[i] * z
+	}
+	rate := (n*sumxz - sumx*sumz) / (n*sumxx - sumx*sumx)
+	intercept := (sumz - rate*sumx) / n
+	midpoint := -intercept / rate
+	if n < 2 || !finite(rate, midpoint) || rate == 0 {
+		//Fall back to a curve spanning the data
+		rate = 4 / (last - first)
+		midpoint = (first + last) / 2
+	}
+	return []float64{capacity, midpoint, rate}
+}
+
+func (m *GrowthModel) Name() string {
+	return m.name
+}
+
+func (m *GrowthModel) String() string {
+	if m.name == "richards" {
+		return fmt.Sprintf("richards curve with capacity %g, midpoint %g, rate %g and shape %g", m.Capacity(), m.Midpoint(), m.Rate(), m.Shape())
+	}
+	return fmt.Sprintf("%s curve with capacity %g, midpoint %g and rate %g", m.name, m.Capacity(), m.Midpoint(), m.Rate())
+}
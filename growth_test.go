@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitGrowth(t *testing.T) {
+	curves := []struct {
+		name   string
+		fit    func(*Series) (FitParameters, error)
+		f      NonlinearFunc
+		params []float64
+	}{
+		{"logistic", (*Series).FitLogisticE, logisticFunction, []float64{100, 15, 0.4}},
+		{"gompertz", (*Series).FitGompertzE, gompertzFunction, []float64{50, 10, 0.3}},
+		{"richards", (*Series).FitRichardsE, richardsFunction, []float64{80, 12, 0.5, 2.5}},
+	}
+	for _, c := range curves {
+		x := make([]float64, 40)
+		y := make([]float64, 40)
+		for i := range x {
+			x[i] = float64(i)
+			y[i] = c.f(x[i], c.params) + 0.2*math.Sin(float64(i))
+		}
+		fit, err := c.fit(NewSeriesFrom(x, y))
+		if err != nil {
+			t.Fatal(c.name, "fit returned", err)
+		}
+		model, ok := fit.Model.(*GrowthModel)
+		if !ok || model.Name() != c.name {
+			t.Fatal(c.name, "fit returned", fit.Model)
+		}
+		if !model.Converged {
+			t.Error(c.name, "fit did not converge:", model.Termination)
+		}
+		named := []float64{model.Capacity(), model.Midpoint(), model.Rate(), model.Shape()}
+		for i, want := range c.params {
+			if math.Abs(named[i]-want) > 0.05*math.Abs(want) {
+				t.Error(c.name, "parameter", i, "was", named[i], ", should be", want)
+			}
+		}
+		if len(model.Coefficients()) != len(c.params) {
+			t.Error(c.name, "coefficients were", model.Coefficients())
+		}
+		if _, _, err := ConfidenceInterval(fit, 20, 0.95); err != nil {
+			t.Error(c.name, "confidence interval returned", err)
+		}
+	}
+
+	//Decreasing curves are fitted with a negative rate
+	x := make([]float64, 30)
+	y := make([]float64, 30)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = logisticFunction(x[i], []float64{10, 12, -0.5})
+	}
+	fit := NewSeriesFrom(x, y).FitLogistic()
+	model := fit.Model.(*GrowthModel)
+	if math.Abs(model.Rate()+0.5) > 1e-3 {
+		t.Error("Decreasing logistic rate was", model.Rate())
+	}
+	//The accessors read the parameters Eval uses
+	model.Params[0] = 20
+	if model.Capacity() != 20 || math.Abs(model.Eval(12)-10) > 1e-9 {
+		t.Error("Edited capacity was", model.Capacity(), "with midpoint value", model.Eval(12))
+	}
+
+	if _, err := NewSeriesFrom([]float64{1, 2, 3}, []float64{1, 2, 3}).FitRichardsE(); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("Richards fit of 3 points returned", err)
+	}
+}
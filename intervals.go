@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"math"
+)
+
+//Models which can estimate the variance of their fitted curve
+type intervalModel interface {
+	Model
+	//Variance of the fitted curve at x
+	meanVariance(x float64) float64
+	//Variance of a new observation about the fitted curve
+	residualVariance() float64
+	degreesOfFreedom() float64
+}
+
+//Parameter uncertainty of a model that is linear in its parameters
+type uncertainty struct {
+	cov    [][]float64 //(XᵀWX)⁻¹ for the model's basis functions
+	sigma2 float64     //Residual variance
+	dof    float64
+}
+
+func (u *uncertainty) setResidualVariance(report *FitReport) {
+	u.sigma2 = report.StandardError * report.StandardError
+	u.dof = report.DegreesOfFreedom
+}
+
+//Returns σ² gᵀ (XᵀWX)⁻¹ g for the basis functions g evaluated at a point
+func (u *uncertainty) variance(g []float64) float64 {
+	if u.cov == nil {
+		return math.NaN()
+	}
+	var v float64
+	for i := range g {
+		for j := range g {
+			v += g[i] * u.cov[i][j] * g[j]
+		}
+	}
+	return u.sigma2 * v
+}
+
+func (u *uncertainty) residualVariance() float64 {
+	return u.sigma2
+}
+
+func (u *uncertainty) degreesOfFreedom() float64 {
+	if u.cov == nil {
+		return math.NaN()
+	}
+	return u.dof
+}
+
+func (m *LinearModel) meanVariance(x float64) float64 {
+	return m.variance([]float64{x - m.XOffset, 1})
+}
+
+func (m *LogarithmicModel) meanVariance(x float64) float64 {
+	return m.variance([]float64{1, math.Log(x - m.XOffset)})
+}
+
+func (m *PolynomialModel) meanVariance(x float64) float64 {
+	//The covariance is of the fitted coefficients
+	if !m.isScaled() {
+		return math.NaN()
+	}
+	g := make([]float64, len(m.scaled))
+	powers((x-m.center)/m.scale, g)
+	return m.variance(g)
+}
+
+func (m *FourierModel) meanVariance(x float64) float64 {
+	g := make([]float64, 2*len(m.Cosine)+1)
+	fourierBasis(x, m.Period, g)
+	return m.variance(g)
+}
+
+func (m *NonlinearModel) meanVariance(x float64) float64 {
+	g := make([]float64, len(m.Params))
+	if m.jacobian != nil {
+		m.jacobian(x, m.Params, g)
+	} else {
+		numericalGradient(m.Function, x, m.Params, g)
+	}
+	var v float64
+	for i := range g {
+		for j := range g {
+			v += g[i] * m.Covariance[i][j] * g[j]
+		}
+	}
+	return v
+}
+
+func (m *NonlinearModel) residualVariance() float64 {
+	return m.SSE / float64(m.dof)
+}
+
+func (m *NonlinearModel) degreesOfFreedom() float64 {
+	return float64(m.dof)
+}
+
+func (m *LowessModel) meanVariance(x float64) float64 {
+	return math.Pow(m.StandardError(x), 2)
+}
+
+func (m *LowessModel) residualVariance() float64 {
+	return m.sigma * m.sigma
+}
+
+func (m *LowessModel) degreesOfFreedom() float64 {
+	return m.dof
+}
+
+//Returns the confidence interval for the fitted curve at x, at a confidence level such as 0.95.
+//Intervals are available for linear, logarithmic, polynomial, Fourier, nonlinear and LOWESS fits.
+func ConfidenceInterval(m Model, x float64, level float64) (lower float64, upper float64, err error) {
+	return interval(m, x, level, false)
+}
+
+//Returns the prediction interval for a new observation at x, at a confidence level such as 0.95.
+//For weighted fits the new observation is assumed to have a weight of 1.
+func PredictionInterval(m Model, x float64, level float64) (lower float64, upper float64, err error) {
+	return interval(m, x, level, true)
+}
+
+//Returns the lower and upper confidence bounds at each x, for plotting alongside the fit.
+func ConfidenceBand(m Model, x []float64, level float64) (lower *Series, upper *Series, err error) {
+	return band(m, x, level, false)
+}
+
+//Returns the lower and upper prediction bounds at each x, for plotting alongside the fit.
+func PredictionBand(m Model, x []float64, level float64) (lower *Series, upper *Series, err error) {
+	return band(m, x, level, true)
+}
+
+func interval(m Model, x float64, level float64, prediction bool) (lower float64, upper float64, err error) {
+	if !(level > 0 && level < 1) {
+		return 0, 0, ErrInvalidArgument
+	}
+	im, ok := unwrapModel(m).(intervalModel)
+	if !ok {
+		return 0, 0, ErrNoCovariance
+	}
+	dof := im.degreesOfFreedom()
+	if !(dof > 0) {
+		return 0, 0, ErrNoCovariance
+	}
+	variance := im.meanVariance(x)
+	if prediction {
+		variance += im.residualVariance()
+	}
+	if !finite(variance) {
+		return 0, 0, ErrNoCovariance
+	}
+	y := im.Eval(x)
+	half := studentTQuantile(1-(1-level)/2, dof) * math.Sqrt(variance)
+	return y - half, y + half, nil
+}
+
+func band(m Model, x []float64, level float64, prediction bool) (lower *Series, upper *Series, err error) {
+	lx := make([]float64, len(x))
+	ly := make([]float64, len(x))
+	ux := make([]float64, len(x))
+	uy := make([]float64, len(x))
+	for i := range x {
+		lx[i], ux[i] = x[i], x[i]
+		ly[i], uy[i], err = interval(m, x[i], level, prediction)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return NewSeriesFrom(lx, ly), NewSeriesFrom(ux, uy), nil
+}
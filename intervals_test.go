@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIntervals(t *testing.T) {
+	if q := studentTQuantile(0.975, 10); math.Abs(q-2.228138851986) > 1e-9 {
+		t.Error("t quantile was", q, ", should be 2.228138851986")
+	}
+
+	s := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{1, 3, 2, 4})
+	half := 4.302652729749464 * math.Sqrt(0.9*0.25)
+	predictionHalf := 4.302652729749464 * math.Sqrt(0.9*1.25)
+
+	for _, fit := range []FitParameters{s.FitLinear(), s.FitPolynomial(1)} {
+		lower, upper, err := ConfidenceInterval(fit, 2.5, 0.95)
+		if err != nil {
+			t.Fatal("ConfidenceInterval returned", err)
+		}
+		if math.Abs(lower-(2.5-half)) > 1e-6 || math.Abs(upper-(2.5+half)) > 1e-6 {
+			t.Error(fit.Name(), "confidence interval was", lower, upper)
+		}
+		lower, upper, _ = PredictionInterval(fit, 2.5, 0.95)
+		if math.Abs(lower-(2.5-predictionHalf)) > 1e-6 || math.Abs(upper-(2.5+predictionHalf)) > 1e-6 {
+			t.Error(fit.Name(), "prediction interval was", lower, upper)
+		}
+	}
+
+	nonlinear := s.FitNonlinear(func(x float64, p []float64) float64 {
+		return p[0]*x + p[1]
+	}, []float64{1, 1}, NonlinearOptions{})
+	lowerBand, upperBand, err := ConfidenceBand(nonlinear, []float64{1, 2.5, 4}, 0.95)
+	if err != nil {
+		t.Fatal("ConfidenceBand returned", err)
+	}
+	if _, l := lowerBand.Point(1); math.Abs(l-(2.5-half)) > 1e-4 {
+		t.Error("Nonlinear lower band was", l, ", should be", 2.5-half)
+	}
+	if _, u := upperBand.Point(1); math.Abs(u-(2.5+half)) > 1e-4 {
+		t.Error("Nonlinear upper band was", u, ", should be", 2.5+half)
+	}
+
+	if _, _, err := ConfidenceInterval(s.FitPower(), 2, 0.95); !errors.Is(err, ErrNoCovariance) {
+		t.Error("ConfidenceInterval returned", err, ", should be", ErrNoCovariance)
+	}
+}
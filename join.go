@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+//Join modes
+const (
+	JoinInner = iota //Only x values present in both series
+	JoinOuter        //Every x value present in either series
+	JoinLeft         //The x values of the first series
+)
+
+//Aligns two series on x, returning a series of the values of each at the same x values.
+//Values missing from a series are filled according to the Fill policy: FillPrevious carries
+//the last earlier value forward, and FillLinear interpolates between the neighbouring points.
+//Either leaves NaN where there is no earlier point, or no later one for FillLinear.
+//Where a series repeats an x value, its last value there is used.
+func Join(a *Series, b *Series, mode int, fill int) (*Series, *Series, error) {
+	if mode < JoinInner || mode > JoinLeft || !validFill(fill) {
+		return nil, nil, ErrInvalidArgument
+	}
+	if err := checkJoin(a, b); err != nil {
+		return nil, nil, err
+	}
+	var x []float64
+	switch mode {
+	case JoinInner:
+		for _, v := range joinX(a) {
+			if i := upperBound(b.x, v); i > 0 && b.x[i-1] == v {
+				x = append(x, v)
+			}
+		}
+	case JoinOuter:
+		x = joinX(a, b)
+	case JoinLeft:
+		x = joinX(a)
+	}
+	return alignAt(x, a, fill), alignAt(x, b, fill), nil
+}
+
+//As-of join: pairs each point of a with the last point of b at or before its x and no more
+//than tolerance earlier.  Points of a without such a point are dropped.
+//Use math.Inf(1) for an unlimited tolerance.
+func AsOf(a *Series, b *Series, tolerance float64) (*Series, *Series, error) {
+	if !(tolerance >= 0) {
+		return nil, nil, ErrInvalidArgument
+	}
+	if err := checkJoin(a, b); err != nil {
+		return nil, nil, err
+	}
+	left, right := NewSeries(), NewSeries()
+	for i, x := range a.x {
+		j := upperBound(b.x, x) - 1
+		if j < 0 || x-b.x[j] > tolerance {
+			continue
+		}
+		left.Add(x, a.y[i])
+		right.Add(x, b.y[j])
+	}
+	return left, right, nil
+}
+
+//Aligns any number of series on every x value present in any of them, as an outer Join
+func Merge(fill int, series ...*Series) ([]*Series, error) {
+	if len(series) == 0 || !validFill(fill) {
+		return nil, ErrInvalidArgument
+	}
+	if err := checkJoin(series...); err != nil {
+		return nil, err
+	}
+	x := joinX(series...)
+	merged := make([]*Series, len(series))
+	for i, s := range series {
+		merged[i] = alignAt(x, s, fill)
+	}
+	return merged, nil
+}
+
+func checkJoin(series ...*Series) error {
+	for _, s := range series {
+		if s == nil {
+			return ErrInvalidArgument
+		}
+		if !finite(s.x...) {
+			return ErrNonFinite
+		}
+		if !s.monotonic() {
+			return ErrNonMonotonicX
+		}
+	}
+	return nil
+}
+
+//The distinct x values of the series, in ascending order
+func joinX(series ...*Series) []float64 {
+	var x []float64
+	for _, s := range series {
+		x = append(x, s.x...)
+	}
+	sort.Float64s(x)
+	distinct := x[:0]
+	for i, v := range x {
+		if i == 0 || v != x[i-1] {
+			distinct = append(distinct, v)
+		}
+	}
+	return distinct
+}
+
+//Index of the first x value greater than v
+func upperBound(x []float64, v float64) int {
+	return sort.Search(len(x), func(i int) bool { return x[i] > v })
+}
+
+//Evaluates the series at each x, filling values it does not have
+func alignAt(x []float64, s *Series, fill int) *Series {
+	y := make([]float64, len(x))
+	for k, v := range x {
+		i := upperBound(s.x, v)
+		if i > 0 && s.x[i-1] == v {
+			y[k] = s.y[i-1]
+			continue
+		}
+		switch {
+		case fill == FillZero:
+			y[k] = 0
+		case fill == FillPrevious && i > 0:
+			y[k] = s.y[i-1]
+		case fill == FillLinear && i > 0 && i < s.Len:
+			y[k] = s.y[i-1] + (s.y[i]-s.y[i-1])*(v-s.x[i-1])/(s.x[i]-s.x[i-1])
+		default:
+			y[k] = math.NaN()
+		}
+	}
+	return NewSeriesFrom(append([]float64{}, x...), y)
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	a := NewSeriesFrom([]float64{1, 2, 4, 6}, []float64{10, 20, 40, 60})
+	b := NewSeriesFrom([]float64{2, 3, 4, 5, 8}, []float64{2, 3, 4, 5, 8})
+
+	cases := []struct {
+		mode  int
+		fill  int
+		x     []float64
+		left  []float64
+		right []float64
+	}{
+		{JoinInner, FillNaN, []float64{2, 4}, []float64{20, 40}, []float64{2, 4}},
+		{JoinLeft, FillPrevious, []float64{1, 2, 4, 6}, []float64{10, 20, 40, 60}, []float64{math.NaN(), 2, 4, 5}},
+		{JoinOuter, FillLinear, []float64{1, 2, 3, 4, 5, 6, 8}, []float64{10, 20, 30, 40, 50, 60, math.NaN()}, []float64{math.NaN(), 2, 3, 4, 5, 6, 8}},
+		{JoinOuter, FillZero, []float64{1, 2, 3, 4, 5, 6, 8}, []float64{10, 20, 0, 40, 0, 60, 0}, []float64{0, 2, 3, 4, 5, 0, 8}},
+	}
+	for n, c := range cases {
+		left, right, err := Join(a, b, c.mode, c.fill)
+		if err != nil {
+			t.Fatal("Join returned", err)
+		}
+		if !sameValues(left.x, c.x) || !sameValues(right.x, c.x) || !sameValues(left.y, c.left) || !sameValues(right.y, c.right) {
+			t.Error("Case", n, "joined", left.x, left.y, right.y)
+		}
+	}
+
+	if _, _, err := Join(a, NewSeriesFrom([]float64{2, 1}, []float64{0, 0}), JoinInner, FillNaN); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("Unordered series returned", err)
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	trades := NewSeriesFrom([]float64{1, 5, 9, 20}, []float64{100, 101, 102, 103})
+	quotes := NewSeriesFrom([]float64{0, 4, 5, 8}, []float64{1, 2, 3, 4})
+
+	left, right, err := AsOf(trades, quotes, 2)
+	if err != nil {
+		t.Fatal("AsOf returned", err)
+	}
+	//The trade at 20 is more than 2 after the last quote
+	if !sameValues(left.x, []float64{1, 5, 9}) || !sameValues(left.y, []float64{100, 101, 102}) || !sameValues(right.y, []float64{1, 3, 4}) {
+		t.Error("AsOf joined", left.x, left.y, right.y)
+	}
+
+	_, right, _ = AsOf(trades, quotes, math.Inf(1))
+	if right.Len != 4 || right.y[3] != 4 {
+		t.Error("Unlimited AsOf joined", right.y)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged, err := Merge(FillPrevious,
+		NewSeriesFrom([]float64{0, 2}, []float64{1, 2}),
+		NewSeriesFrom([]float64{1, 1, 3}, []float64{5, 6, 7}),
+		NewSeriesFrom([]float64{2}, []float64{9}))
+	if err != nil {
+		t.Fatal("Merge returned", err)
+	}
+	want := [][]float64{{1, 1, 2, 2}, {math.NaN(), 6, 6, 7}, {math.NaN(), math.NaN(), 9, 9}}
+	for i := range want {
+		if !sameValues(merged[i].x, []float64{0, 1, 2, 3}) || !sameValues(merged[i].y, want[i]) {
+			t.Error("Merged series", i, "was", merged[i].x, merged[i].y, ", should be", want[i])
+		}
+	}
+}
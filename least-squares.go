@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"math"
+)
+
+//The solution of a linear least squares problem
+type leastSquares struct {
+	coef      []float64
+	r         [][]float64 //Upper triangular factor of the (weighted) design matrix
+	sse       float64     //Weighted sum of squared residuals
+	condition float64     //2-norm condition number of the design matrix
+}
+
+//Solves min Σ w (y - design·coef)² by Householder QR, which avoids squaring the
+//condition number as forming the normal equations does.  design has one row per point.
+//Returns ErrSingularMatrix when the design matrix is rank deficient.
+func solveLeastSquares(design [][]float64, y []float64, weights []float64) (*leastSquares, error) {
+	n := len(design)
+	if n == 0 {
+		return nil, ErrInsufficientPoints
+	}
+	k := len(design[0])
+	if n < k {
+		return nil, ErrInsufficientPoints
+	}
+
+	a := make([][]float64, n)
+	b := make([]float64, n)
+	for i := range design {
+		w := math.Sqrt(weightAt(weights, i))
+		a[i] = make([]float64, k)
+		for j := range design[i] {
+			a[i][j] = w * design[i][j]
+		}
+		b[i] = w * y[i]
+	}
+
+	v := make([]float64, n)
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := j; i < n; i++ {
+			norm += a[i][j] * a[i][j]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		alpha := -math.Copysign(norm, a[j][j])
+		var vnorm float64
+		for i := j; i < n; i++ {
+			v[i] = a[i][j]
+			if i == j {
+				v[i] -= alpha
+			}
+			vnorm += v[i] * v[i]
+		}
+		for c := j; c < k; c++ {
+			var s float64
+			for i := j; i < n; i++ {
+				s += v[i] * a[i][c]
+			}
+			s = 2 * s / vnorm
+			for i := j; i < n; i++ {
+				a[i][c] -= s * v[i]
+			}
+		}
+		var s float64
+		for i := j; i < n; i++ {
+			s += v[i] * b[i]
+		}
+		s = 2 * s / vnorm
+		for i := j; i < n; i++ {
+			b[i] -= s * v[i]
+		}
+	}
+
+	r := newMatrix(k, k, 0)
+	var maxDiag float64
+	for i := 0; i < k; i++ {
+		copy(r[i][i:], a[i][i:])
+		maxDiag = math.Max(maxDiag, math.Abs(r[i][i]))
+	}
+	tolerance := float64(n) * 2.2e-16 * maxDiag
+	for i := 0; i < k; i++ {
+		if !(math.Abs(r[i][i]) > tolerance) {
+			return nil, ErrSingularMatrix
+		}
+	}
+
+	coef := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		sum := b[i]
+		for j := i + 1; j < k; j++ {
+			sum -= r[i][j] * coef[j]
+		}
+		coef[i] = sum / r[i][i]
+	}
+	var sse float64
+	for i := k; i < n; i++ {
+		sse += b[i] * b[i]
+	}
+
+	sv := singularValues(r)
+	return &leastSquares{
+		coef:      coef,
+		r:         r,
+		sse:       sse,
+		condition: maxFloat(sv) / minFloat(sv),
+	}, nil
+}
+
+//Returns (XᵀWX)⁻¹ = R⁻¹R⁻ᵀ, which scaled by the residual variance is the covariance of the coefficients
+func (ls *leastSquares) unscaledCovariance() [][]float64 {
+	k := len(ls.r)
+	//Invert the upper triangular R by back substitution
+	inv := newMatrix(k, k, 0)
+	for j := 0; j < k; j++ {
+		inv[j][j] = 1 / ls.r[j][j]
+		for i := j - 1; i >= 0; i-- {
+			var sum float64
+			for l := i + 1; l <= j; l++ {
+				sum += ls.r[i][l] * inv[l][j]
+			}
+			inv[i][j] = -sum / ls.r[i][i]
+		}
+	}
+	cov := newMatrix(k, k, 0)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			for l := 0; l < k; l++ {
+				cov[i][j] += inv[i][l] * inv[j][l]
+			}
+		}
+	}
+	return cov
+}
+
+//Singular values of a matrix by one-sided Jacobi rotations
+func singularValues(a [][]float64) []float64 {
+	m := len(a)
+	n := len(a[0])
+	u := make([][]float64, m)
+	for i := range a {
+		u[i] = append([]float64{}, a[i]...)
+	}
+	for sweep := 0; sweep < 60; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta, gamma float64
+				for i := 0; i < m; i++ {
+					alpha += u[i][p] * u[i][p]
+					beta += u[i][q] * u[i][q]
+					gamma += u[i][p] * u[i][q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for i := 0; i < m; i++ {
+					up, uq := u[i][p], u[i][q]
+					u[i][p] = c*up - s*uq
+					u[i][q] = s*up + c*uq
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+	sv := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < m; i++ {
+			sv[j] += u[i][j] * u[i][j]
+		}
+		sv[j] = math.Sqrt(sv[j])
+	}
+	return sv
+}
+
+func maxFloat(values []float64) float64 {
+	max := math.Inf(-1)
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+func minFloat(values []float64) float64 {
+	min := math.Inf(1)
+	for _, v := range values {
+		min = math.Min(min, v)
+	}
+	return min
+}
@@ -0,0 +1,275 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+//Options for FitLowess
+type LowessOptions struct {
+	//Fraction of the points used in each local fit, in the range (0, 1]
+	Bandwidth float64
+	//Degree of the local polynomials, 1 (default) or 2
+	Degree int
+	//Number of Cleveland bisquare robustness iterations, which down-weight outliers
+	RobustnessIterations int
+}
+
+//A locally weighted regression (LOWESS) smoother.  The curve at x is a weighted
+//least squares polynomial fitted to the nearest points, weighted by the tricube of
+//their distance from x and by the robustness weights of the final iteration.
+type LowessModel struct {
+	x          []float64
+	y          []float64
+	robustness []float64
+	fitted     []float64
+	span       int
+	degree     int
+	sigma      float64
+	dof        float64
+	trace      float64
+	Bandwidth  float64
+}
+
+//Smooths the series by local linear regression, returning the smoothed values at the original x.
+//The bandwidth is the fraction of points used in each local fit, and is raised as needed
+//so that each local fit uses at least 2 points.
+func (ts *Series) FitLoess(bandwidth float64) (points *Series) {
+	model := ts.newLowessModel(LowessOptions{Bandwidth: bandwidth, Degree: 1})
+	model.smooth()
+	x := make([]float64, ts.Len)
+	copy(x, ts.x)
+	return NewSeriesFrom(x, model.fitted)
+}
+
+//As FitLoess, but returns an error for invalid input rather than panicking.
+//The bandwidth must be in the range (0, 1].
+func (ts *Series) FitLoessE(bandwidth float64) (*Series, error) {
+	if !(bandwidth > 0 && bandwidth <= 1) {
+		return nil, ErrInvalidArgument
+	}
+	if err := ts.checkFit(2); err != nil {
+		return nil, err
+	}
+	return ts.FitLoess(bandwidth), nil
+}
+
+//Fits a LOWESS model which can be evaluated at any x, along with the standard error
+//of the smoothed curve for confidence bands.
+func (ts *Series) FitLowess(opts LowessOptions) FitParameters {
+	return mustFit(ts.FitLowessE(opts))
+}
+
+//As FitLowess, but returns an error for invalid input.
+func (ts *Series) FitLowessE(opts LowessOptions) (params FitParameters, err error) {
+	if opts.Degree == 0 {
+		opts.Degree = 1
+	}
+	if !(opts.Bandwidth > 0 && opts.Bandwidth <= 1) || opts.Degree < 1 || opts.Degree > 2 || opts.RobustnessIterations < 0 {
+		return params, ErrInvalidArgument
+	}
+	if err = ts.checkFit(opts.Degree + 1); err != nil {
+		return
+	}
+
+	model := ts.newLowessModel(opts)
+	residuals := make([]float64, ts.Len)
+	for iteration := 0; ; iteration++ {
+		model.smooth()
+		for i := range residuals {
+			residuals[i] = math.Abs(ts.y[i] - model.fitted[i])
+		}
+		if iteration == opts.RobustnessIterations {
+			break
+		}
+		s := median(residuals)
+		if s == 0 {
+			break
+		}
+		for i := range residuals {
+			model.robustness[i] = bisquare(residuals[i] / (6 * s))
+		}
+	}
+
+	//Residual variance, using the trace of the smoothing matrix as the number of parameters
+	//and excluding points rejected by the robustness iterations
+	var sse, n, trace float64
+	for i := range residuals {
+		sse += model.robustness[i] * residuals[i] * residuals[i]
+		n += model.robustness[i]
+		trace += model.local(model.x[i])[i]
+	}
+	model.sigma = math.NaN()
+	model.trace = trace
+	model.dof = n - trace
+	if model.dof > 0 {
+		model.sigma = math.Sqrt(sse / model.dof)
+	}
+
+	params = FitParameters{
+		Type:  FitTypeLowess,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+func (ts *Series) newLowessModel(opts LowessOptions) *LowessModel {
+	model := &LowessModel{
+		x:          make([]float64, ts.Len),
+		y:          make([]float64, ts.Len),
+		robustness: make([]float64, ts.Len),
+		span:       int(math.Ceil(opts.Bandwidth * float64(ts.Len))),
+		degree:     opts.Degree,
+		Bandwidth:  opts.Bandwidth,
+	}
+	copy(model.x, ts.x)
+	copy(model.y, ts.y)
+	for i := range model.robustness {
+		model.robustness[i] = 1
+	}
+	if model.span < opts.Degree+1 {
+		model.span = opts.Degree + 1
+	}
+	if model.span > ts.Len {
+		model.span = ts.Len
+	}
+	return model
+}
+
+//Evaluates the smoothed curve at each of the original x
+func (m *LowessModel) smooth() {
+	m.fitted = make([]float64, len(m.x))
+	for i := range m.x {
+		m.fitted[i] = m.Eval(m.x[i])
+	}
+}
+
+//Returns the weights l such that the smoothed value at x0 is Σ l[i] y[i]
+func (m *LowessModel) local(x0 float64) []float64 {
+	//The nearest span points form a contiguous window of the sorted x values
+	n := len(m.x)
+	right := sort.SearchFloat64s(m.x, x0)
+	left := right
+	for right-left < m.span {
+		if left == 0 {
+			right++
+		} else if right == n || x0-m.x[left-1] <= m.x[right]-x0 {
+			left--
+		} else {
+			right++
+		}
+	}
+	h := math.Max(x0-m.x[left], m.x[right-1]-x0)
+
+	l := make([]float64, n)
+	w := make([]float64, n)
+	for i := left; i < right; i++ {
+		if h == 0 {
+			w[i] = m.robustness[i]
+		} else {
+			//Widen slightly so that the furthest neighbour keeps a small weight
+			w[i] = tricube(math.Abs(m.x[i]-x0)/(h*1.001)) * m.robustness[i]
+		}
+	}
+
+	//Local polynomial in t = (x - x0) / h, falling back to lower degrees when singular
+	if h == 0 {
+		h = 1
+	}
+	for degree := m.degree; degree >= 0; degree-- {
+		k := degree + 1
+		xtwx := newMatrix(k, k, 0)
+		basis := make([]float64, k)
+		for i := left; i < right; i++ {
+			powers((m.x[i]-x0)/h, basis)
+			for a := 0; a < k; a++ {
+				for b := 0; b < k; b++ {
+					xtwx[a][b] += w[i] * basis[a] * basis[b]
+				}
+			}
+		}
+		e1 := make([]float64, k)
+		e1[0] = 1
+		z, err := solveLinear(xtwx, e1)
+		if err != nil {
+			continue
+		}
+		for i := left; i < right; i++ {
+			powers((m.x[i]-x0)/h, basis)
+			var dot float64
+			for a := range z {
+				dot += z[a] * basis[a]
+			}
+			l[i] = w[i] * dot
+		}
+		return l
+	}
+	return l
+}
+
+//Fills basis with 1, t, t^2, ...
+func powers(t float64, basis []float64) {
+	basis[0] = 1
+	for a := 1; a < len(basis); a++ {
+		basis[a] = basis[a-1] * t
+	}
+}
+
+func (m *LowessModel) Eval(x float64) float64 {
+	l := m.local(x)
+	var y float64
+	for i := range l {
+		y += l[i] * m.y[i]
+	}
+	return y
+}
+
+//Standard error of the smoothed curve at x
+func (m *LowessModel) StandardError(x float64) float64 {
+	l := m.local(x)
+	var sum float64
+	for i := range l {
+		sum += l[i] * l[i]
+	}
+	return m.sigma * math.Sqrt(sum)
+}
+
+//Returns the smoothed values at the fitted x
+func (m *LowessModel) Coefficients() []float64 {
+	return append([]float64{}, m.fitted...)
+}
+
+//Returns the robustness weight of each fitted point
+func (m *LowessModel) RobustnessWeights() []float64 {
+	return append([]float64{}, m.robustness...)
+}
+
+//The effective number of parameters, the trace of the smoothing matrix
+func (m *LowessModel) Parameters() float64 {
+	return m.trace
+}
+
+func (m *LowessModel) Name() string {
+	return "lowess"
+}
+
+func (m *LowessModel) String() string {
+	return fmt.Sprintf("lowess of degree %d with bandwidth %g over %d points", m.degree, m.Bandwidth, len(m.x))
+}
+
+func tricube(x float64) float64 {
+	if x >= 1 {
+		return 0
+	}
+	var tmp = 1 - math.Pow(x, 3)
+	return math.Pow(tmp, 3)
+}
+
+func bisquare(x float64) float64 {
+	if math.Abs(x) >= 1 {
+		return 0
+	}
+	return math.Pow(1-x*x, 2)
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitLowess(t *testing.T) {
+	x := make([]float64, 50)
+	y := make([]float64, 50)
+	for i := range x {
+		x[i] = float64(i) / 5
+		y[i] = x[i]*x[i] + 0.1*math.Sin(float64(i*7))
+	}
+	y[20] = 100
+	s := NewSeriesFrom(x, y)
+
+	fit, err := s.FitLowessE(LowessOptions{Bandwidth: 0.3, Degree: 2, RobustnessIterations: 3})
+	if err != nil {
+		t.Fatal("FitLowessE returned", err)
+	}
+	model := fit.Model.(*LowessModel)
+
+	//Evaluated between the original points, unaffected by the outlier
+	for _, xi := range []float64{1.1, 4.05, 8.3} {
+		if v := fit.Eval(xi); math.Abs(v-xi*xi) > 0.2 {
+			t.Error("Lowess evaluated to", v, "at", xi, ", should be close to", xi*xi)
+		}
+	}
+	if w := model.RobustnessWeights()[20]; w != 0 {
+		t.Error("Outlier robustness weight was", w, ", should be 0")
+	}
+	if se := model.StandardError(5); !(se > 0 && se < 0.2) {
+		t.Error("Standard error was", se)
+	}
+
+	smoothed := NewSeriesFrom(x[:10], x[:10]).FitLoess(0.5)
+	for i := 0; i < smoothed.Len; i++ {
+		if sx, sy := smoothed.Point(i); math.Abs(sx-sy) > 1e-9 {
+			t.Error("Loess of a line was", sy, "at", sx)
+		}
+	}
+}
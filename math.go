@@ -1,6 +1,9 @@
 package analytics
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 func round(f float64) float64 {
 	if math.Abs(f) < 0.5 {
@@ -8,3 +11,123 @@ func round(f float64) float64 {
 	}
 	return float64(int(f + math.Copysign(0.5, f)))
 }
+
+//Solves a tridiagonal system using the Thomas algorithm.  sub[0] and sup[n-1] are ignored.
+func solveTridiagonal(sub []float64, diag []float64, sup []float64, rhs []float64) ([]float64, error) {
+	n := len(diag)
+	c := make([]float64, n)
+	d := make([]float64, n)
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		pivot := diag[i]
+		if i > 0 {
+			pivot -= sub[i] * c[i-1]
+		}
+		if pivot == 0 || !finite(pivot) {
+			return nil, ErrSingularMatrix
+		}
+		if i < n-1 {
+			c[i] = sup[i] / pivot
+		}
+		d[i] = rhs[i]
+		if i > 0 {
+			d[i] -= sub[i] * d[i-1]
+		}
+		d[i] /= pivot
+	}
+	for i := n - 1; i >= 0; i-- {
+		x[i] = d[i]
+		if i < n-1 {
+			x[i] -= c[i] * x[i+1]
+		}
+	}
+	return x, nil
+}
+
+//Solves a x = b by gaussian elimination with partial pivoting.  The inputs are not modified.
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n+1)
+		copy(m[i], a[i])
+		m[i][n] = b[i]
+	}
+	for i := 0; i < n; i++ {
+		maxrow := i
+		for j := i + 1; j < n; j++ {
+			if math.Abs(m[j][i]) > math.Abs(m[maxrow][i]) {
+				maxrow = j
+			}
+		}
+		m[i], m[maxrow] = m[maxrow], m[i]
+		if m[i][i] == 0 || !finite(m[i][i]) {
+			return nil, ErrSingularMatrix
+		}
+		for j := i + 1; j < n; j++ {
+			f := m[j][i] / m[i][i]
+			for k := i; k <= n; k++ {
+				m[j][k] -= f * m[i][k]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := m[i][n]
+		for k := i + 1; k < n; k++ {
+			sum -= m[i][k] * x[k]
+		}
+		x[i] = sum / m[i][i]
+	}
+	return x, nil
+}
+
+//Inverts a square matrix.  The input is not modified.
+func invert(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = make([]float64, n)
+	}
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := range e {
+			e[i] = 0
+		}
+		e[j] = 1
+		col, err := solveLinear(a, e)
+		if err != nil {
+			return nil, err
+		}
+		for i := range col {
+			inv[i][j] = col[i]
+		}
+	}
+	return inv, nil
+}
+
+//Creates an n by m matrix filled with value
+func newMatrix(n int, m int, value float64) [][]float64 {
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, m)
+		for j := range a[i] {
+			a[i][j] = value
+		}
+	}
+	return a
+}
+
+//Median of the values.  The input is not modified.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+//Criteria used to score models in FitBest.  Lower scores are better.
+const (
+	CriterionAIC = iota
+	CriterionBIC
+	CriterionCV //Cross-validated root mean squared error
+)
+
+//Controls the models tried by FitBest and how they are scored.
+type FitBestOptions struct {
+	Criterion          int //One of the Criterion constants
+	MaxPolynomialOrder int //Polynomials of order 2 to MaxPolynomialOrder are tried, defaults to 3; 1 tries none
+	Folds              int //Number of cross-validation folds, defaults to 5
+}
+
+//A fit and its score under the selection criterion
+type RankedFit struct {
+	FitParameters
+	Score float64
+}
+
+//Tries every applicable fit type and returns them ranked from best to worst.
+//Fits that fail, or whose curve or score is undefined over the data, are left out.
+//Returns nil if no model could be fitted.
+func (ts *Series) FitBest(opts FitBestOptions) []RankedFit {
+	ranked, _ := ts.FitBestE(opts)
+	return ranked
+}
+
+//As FitBest, but returns an error for invalid input, or ErrNoFit when no model could be fitted.
+func (ts *Series) FitBestE(opts FitBestOptions) ([]RankedFit, error) {
+	if opts.Criterion < CriterionAIC || opts.Criterion > CriterionCV || opts.MaxPolynomialOrder < 0 || opts.Folds < 0 || opts.Folds == 1 {
+		return nil, ErrInvalidArgument
+	}
+	if opts.Folds == 0 {
+		opts.Folds = 5
+	}
+	if opts.MaxPolynomialOrder == 0 {
+		opts.MaxPolynomialOrder = 3
+	}
+	if err := ts.checkFit(2); err != nil {
+		return nil, err
+	}
+	folds := opts.Folds
+	if folds > ts.Len {
+		folds = ts.Len
+	}
+
+	ranked := []RankedFit{}
+	for _, candidate := range bestFitCandidates(opts.MaxPolynomialOrder) {
+		params, err := candidate(ts)
+		if err != nil {
+			continue
+		}
+		var score float64
+		switch opts.Criterion {
+		case CriterionAIC:
+			score = params.Report.AIC
+		case CriterionBIC:
+			score = params.Report.BIC
+		case CriterionCV:
+			score = math.NaN()
+			cv, err := ts.CrossValidate(candidate, folds)
+			if err == nil && finite(cv.RMSE) {
+				score = cv.RMSE
+			}
+		}
+		//An infinite score would always rank first or last regardless of the fit
+		if !finite(score, params.Report.SSE) {
+			continue
+		}
+		ranked = append(ranked, RankedFit{FitParameters: params, Score: score})
+	}
+	if len(ranked) == 0 {
+		return nil, ErrNoFit
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score < ranked[j].Score
+	})
+	return ranked, nil
+}
+
+func bestFitCandidates(maxOrder int) []FitFunc {
+	candidates := []FitFunc{
+		(*Series).FitLinearE,
+		(*Series).FitLinearThroughOriginE,
+		(*Series).FitLogarithmicE,
+		(*Series).FitExponentialE,
+		(*Series).FitPowerE,
+		//The parabola in ln(y) is the same curve, so only the Gaussian is ranked
+		func(s *Series) (FitParameters, error) {
+			fits, err := s.FitGaussianParabolicE()
+			if err != nil {
+				return FitParameters{}, err
+			}
+			return fits[1], nil
+		},
+	}
+	for order := 2; order <= maxOrder; order++ {
+		order := order
+		candidates = append(candidates, func(s *Series) (FitParameters, error) {
+			return s.FitPolynomialE(order)
+		})
+	}
+	return candidates
+}
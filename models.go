@@ -0,0 +1,381 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//A fitted curve.  Models are returned by the Fit functions wrapped in FitParameters,
+//and can be evaluated directly or through Extrapolate.  Implement Model to use
+//custom curves wherever a fit is accepted.
+//
+//A model may also implement Parameters() float64, the number of parameters estimated from
+//the data, which sets the degrees of freedom of the fit.  Smoothers report their effective
+//number, which may be fractional.  Models without it count their Coefficients.
+type Model interface {
+	//Evaluates the curve at x
+	Eval(x float64) float64
+	//The fitted coefficients, in the order documented by the model
+	Coefficients() []float64
+	//Short name of the model, e.g. "linear"
+	Name() string
+	//The fitted equation
+	String() string
+}
+
+//y = Gradient * (x - XOffset) + Intercept + YOffset
+type LinearModel struct {
+	Gradient    float64
+	Intercept   float64
+	Correlation float64
+	XOffset     float64
+	YOffset     float64
+	uncertainty
+}
+
+func (m *LinearModel) Eval(x float64) float64 {
+	return (x-m.XOffset)*m.Gradient + m.Intercept + m.YOffset
+}
+
+//Returns gradient, intercept
+func (m *LinearModel) Coefficients() []float64 {
+	return []float64{m.Gradient, m.Intercept}
+}
+
+func (m *LinearModel) Parameters() float64 {
+	return 2
+}
+
+func (m *LinearModel) Name() string {
+	return "linear"
+}
+
+func (m *LinearModel) String() string {
+	return fmt.Sprintf("y = %g(x - %g) + %g + %g", m.Gradient, m.XOffset, m.Intercept, m.YOffset)
+}
+
+//y = Gradient * x
+type LinearThroughOriginModel struct {
+	Gradient float64
+}
+
+func (m *LinearThroughOriginModel) Eval(x float64) float64 {
+	return x * m.Gradient
+}
+
+//Returns gradient
+func (m *LinearThroughOriginModel) Coefficients() []float64 {
+	return []float64{m.Gradient}
+}
+
+func (m *LinearThroughOriginModel) Parameters() float64 {
+	return 1
+}
+
+func (m *LinearThroughOriginModel) Name() string {
+	return "linear through origin"
+}
+
+func (m *LinearThroughOriginModel) String() string {
+	return fmt.Sprintf("y = %gx", m.Gradient)
+}
+
+//y = A + B * ln(x - XOffset) + YOffset
+type LogarithmicModel struct {
+	A       float64
+	B       float64
+	XOffset float64
+	YOffset float64
+	uncertainty
+}
+
+func (m *LogarithmicModel) Eval(x float64) float64 {
+	return m.A + m.B*math.Log(x-m.XOffset) + m.YOffset
+}
+
+//Returns A, B
+func (m *LogarithmicModel) Coefficients() []float64 {
+	return []float64{m.A, m.B}
+}
+
+func (m *LogarithmicModel) Parameters() float64 {
+	return 2
+}
+
+func (m *LogarithmicModel) Name() string {
+	return "logarithmic"
+}
+
+func (m *LogarithmicModel) String() string {
+	return fmt.Sprintf("y = %g + %g ln(x - %g) + %g", m.A, m.B, m.XOffset, m.YOffset)
+}
+
+//y = A * (x - XOffset)^B + YOffset
+type PowerModel struct {
+	A       float64
+	B       float64
+	XOffset float64
+	YOffset float64
+}
+
+func (m *PowerModel) Eval(x float64) float64 {
+	return m.A*math.Pow(x-m.XOffset, m.B) + m.YOffset
+}
+
+//Returns A, B
+func (m *PowerModel) Coefficients() []float64 {
+	return []float64{m.A, m.B}
+}
+
+func (m *PowerModel) Parameters() float64 {
+	return 2
+}
+
+func (m *PowerModel) Name() string {
+	return "power"
+}
+
+func (m *PowerModel) String() string {
+	return fmt.Sprintf("y = %g(x - %g)^%g + %g", m.A, m.XOffset, m.B, m.YOffset)
+}
+
+//y = A * e^(B * (x - XOffset)) + YOffset
+type ExponentialModel struct {
+	A       float64
+	B       float64
+	XOffset float64
+	YOffset float64
+}
+
+func (m *ExponentialModel) Eval(x float64) float64 {
+	return m.A*math.Exp(m.B*(x-m.XOffset)) + m.YOffset
+}
+
+//Returns A, B
+func (m *ExponentialModel) Coefficients() []float64 {
+	return []float64{m.A, m.B}
+}
+
+func (m *ExponentialModel) Parameters() float64 {
+	return 2
+}
+
+func (m *ExponentialModel) Name() string {
+	return "exponential"
+}
+
+func (m *ExponentialModel) String() string {
+	return fmt.Sprintf("y = %ge^(%g(x - %g)) + %g", m.A, m.B, m.XOffset, m.YOffset)
+}
+
+//y = Σ Coeffs[i] * (x - XOffset)^i + YOffset
+//
+//While Coeffs and XOffset are unchanged from the fit, the polynomial is evaluated
+//internally in terms of x scaled to [-1, 1] over the fitted range, which is better
+//conditioned than Coeffs at high order.  Once they are edited Coeffs are used directly.
+type PolynomialModel struct {
+	Coeffs    []float64
+	XOffset   float64
+	YOffset   float64
+	Condition float64 //Condition number of the scaled least squares problem
+	center    float64
+	scale     float64
+	scaled    []float64
+	fitted    []float64 //Coeffs as fitted
+	fittedX   float64   //XOffset as fitted
+	uncertainty
+}
+
+//Creates a polynomial from coefficients of the scaled variable (x - center) / scale
+func newScaledPolynomial(scaled []float64, center float64, scale float64, xoffset float64, yoffset float64) *PolynomialModel {
+	m := &PolynomialModel{
+		XOffset: xoffset,
+		YOffset: yoffset,
+		center:  center,
+		scale:   scale,
+		scaled:  scaled,
+	}
+
+	//Expand Σ s[j] ((x' - c) / scale)^j, where x' = x - XOffset and c = center - XOffset
+	c := center - xoffset
+	m.Coeffs = make([]float64, len(scaled))
+	for j := range scaled {
+		binomial := 1.0
+		for i := 0; i <= j; i++ {
+			m.Coeffs[i] += scaled[j] * binomial * math.Pow(-c, float64(j-i)) / math.Pow(scale, float64(j))
+			binomial = binomial * float64(j-i) / float64(i+1)
+		}
+	}
+	m.fitted = append([]float64{}, m.Coeffs...)
+	m.fittedX = xoffset
+	return m
+}
+
+//Reports whether the scaled coefficients still describe Coeffs and XOffset
+func (m *PolynomialModel) isScaled() bool {
+	if m.scaled == nil || m.XOffset != m.fittedX || len(m.Coeffs) != len(m.fitted) {
+		return false
+	}
+	for i := range m.Coeffs {
+		if m.Coeffs[i] != m.fitted[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//Returns the coefficients, excluding YOffset, in the variable t = (x - center) / scale
+func (m *PolynomialModel) form() (c []float64, center float64, scale float64) {
+	if m.isScaled() {
+		return m.scaled, m.center, m.scale
+	}
+	return m.Coeffs, m.XOffset, 1
+}
+
+func (m *PolynomialModel) Eval(x float64) float64 {
+	c, center, scale := m.form()
+	t := (x - center) / scale
+	var answer float64 = 0
+	for i := len(c) - 1; i >= 0; i-- {
+		answer = answer*t + c[i]
+	}
+	return answer + m.YOffset
+}
+
+//Returns the coefficients in ascending order of power
+func (m *PolynomialModel) Coefficients() []float64 {
+	return append([]float64{}, m.Coeffs...)
+}
+
+//The order of the polynomial
+func (m *PolynomialModel) Order() int {
+	return len(m.Coeffs) - 1
+}
+
+func (m *PolynomialModel) Parameters() float64 {
+	return float64(len(m.Coeffs))
+}
+
+func (m *PolynomialModel) Name() string {
+	return "polynomial"
+}
+
+func (m *PolynomialModel) String() string {
+	s := "y ="
+	for i, c := range m.Coeffs {
+		if i > 0 {
+			s += " +"
+		}
+		s += fmt.Sprintf(" %g(x - %g)^%d", c, m.XOffset, i)
+	}
+	return s + fmt.Sprintf(" + %g", m.YOffset)
+}
+
+//The parabola ln(y - YOffset) = A * (x - XOffset)^2 + B * (x - XOffset) + C fitted by
+//FitGaussianParabolic.  Eval returns y, the Gaussian the parabola describes in ln(y);
+//LogEval returns the parabola itself.
+type ParabolicModel struct {
+	A       float64
+	B       float64
+	C       float64
+	XOffset float64
+	YOffset float64
+}
+
+func (m *ParabolicModel) Eval(x float64) float64 {
+	return math.Exp(m.LogEval(x)) + m.YOffset
+}
+
+//Evaluates the parabola, ln(y - YOffset), at x
+func (m *ParabolicModel) LogEval(x float64) float64 {
+	x -= m.XOffset
+	return m.A*x*x + m.B*x + m.C
+}
+
+//Returns A, B, C
+func (m *ParabolicModel) Coefficients() []float64 {
+	return []float64{m.A, m.B, m.C}
+}
+
+func (m *ParabolicModel) Parameters() float64 {
+	return 3
+}
+
+func (m *ParabolicModel) Name() string {
+	return "parabolic"
+}
+
+func (m *ParabolicModel) String() string {
+	return fmt.Sprintf("y = e^(%g(x - %g)^2 + %g(x - %g) + %g) + %g", m.A, m.XOffset, m.B, m.XOffset, m.C, m.YOffset)
+}
+
+//A gaussian peak with the given height, position and full width at half maximum
+type GaussianModel struct {
+	Height   float64
+	Position float64
+	Width    float64
+	XOffset  float64
+	YOffset  float64
+}
+
+func (m *GaussianModel) Eval(x float64) float64 {
+	return (m.Height * math.Exp(-1*math.Pow(((x-m.XOffset)-m.Position)/(0.6006*m.Width), 2))) + m.YOffset
+}
+
+//Returns height, position, width
+func (m *GaussianModel) Coefficients() []float64 {
+	return []float64{m.Height, m.Position, m.Width}
+}
+
+func (m *GaussianModel) Parameters() float64 {
+	return 3
+}
+
+func (m *GaussianModel) Name() string {
+	return "gaussian"
+}
+
+func (m *GaussianModel) String() string {
+	return fmt.Sprintf("y = %ge^-(((x - %g) - %g) / (0.6006 * %g))^2 + %g", m.Height, m.XOffset, m.Position, m.Width, m.YOffset)
+}
+
+//Evaluates a model at each x, e.g. to resample a series onto a uniform grid
+func Interpolate(m Model, x []float64) *Series {
+	newx := make([]float64, len(x))
+	newy := make([]float64, len(x))
+	copy(newx, x)
+	for i := range x {
+		newy[i] = m.Eval(x[i])
+	}
+	return NewSeriesFrom(newx, newy)
+}
+
+//The number of parameters estimated by a model, from its Parameters method if it has one
+func parameterCount(m Model) float64 {
+	if p, ok := m.(interface{ Parameters() float64 }); ok {
+		return p.Parameters()
+	}
+	return float64(len(m.Coefficients()))
+}
+
+//Removes the FitParameters wrapper from a model, returning nil for a missing model
+//or a nil pointer of a model type
+func unwrapModel(m Model) Model {
+	switch p := m.(type) {
+	case FitParameters:
+		m = p.Model
+	case *FitParameters:
+		if p == nil {
+			return nil
+		}
+		m = p.Model
+	}
+	if m == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(m); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,270 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+)
+
+//A model function evaluated at x with parameters p
+type NonlinearFunc func(x float64, p []float64) float64
+
+//Options for FitNonlinear.  The zero value uses a numerical Jacobian, no bounds and default tolerances.
+type NonlinearOptions struct {
+	//Optional analytic Jacobian, which fills grad with ∂f/∂p at x
+	Jacobian func(x float64, p []float64, grad []float64)
+	//Optional bounds on each parameter.  Use ±Inf for unbounded parameters.
+	Lower []float64
+	Upper []float64
+	//Maximum number of iterations, defaults to 200
+	MaxIterations int
+	//Relative change in the sum of squares or the parameters at which the fit has converged, defaults to 1e-10
+	Tolerance float64
+}
+
+//Termination reasons reported by NonlinearModel
+const (
+	TerminationCost      = "relative change in sum of squares below tolerance"
+	TerminationStep      = "relative change in parameters below tolerance"
+	TerminationGradient  = "gradient is zero"
+	TerminationStalled   = "no step reduces the sum of squares"
+	TerminationIteration = "maximum iterations reached"
+)
+
+//A model fitted by nonlinear least squares, together with convergence diagnostics.
+type NonlinearModel struct {
+	Function    NonlinearFunc
+	Params      []float64
+	Covariance  [][]float64 //Parameter covariance, s²(JᵀJ)⁻¹
+	StdErrors   []float64   //Standard error of each parameter
+	SSE         float64     //Sum of squared residuals at the solution
+	Iterations  int
+	Converged   bool   //False if the iterations ran out or stalled before meeting a tolerance
+	Termination string //One of the Termination constants
+	jacobian    func(x float64, p []float64, grad []float64)
+	dof         int
+}
+
+//Fits an arbitrary model function to the series with the Levenberg–Marquardt algorithm,
+//starting from the initial parameters.
+func (ts *Series) FitNonlinear(f NonlinearFunc, initial []float64, opts NonlinearOptions) FitParameters {
+	return mustFit(ts.FitNonlinearE(f, initial, opts))
+}
+
+//As FitNonlinear, but returns an error for invalid input.
+//A fit that fails to converge is not an error; check Converged on the returned model.
+func (ts *Series) FitNonlinearE(f NonlinearFunc, initial []float64, opts NonlinearOptions) (params FitParameters, err error) {
+	model, err := ts.levenbergMarquardt(f, initial, opts)
+	if err != nil {
+		return
+	}
+	params = FitParameters{
+		Type:  FitTypeNonlinear,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+func (ts *Series) levenbergMarquardt(f NonlinearFunc, initial []float64, opts NonlinearOptions) (*NonlinearModel, error) {
+	k := len(initial)
+	if f == nil || k == 0 || opts.MaxIterations < 0 || opts.Tolerance < 0 {
+		return nil, ErrInvalidArgument
+	}
+	if (opts.Lower != nil && len(opts.Lower) != k) || (opts.Upper != nil && len(opts.Upper) != k) {
+		return nil, ErrInvalidArgument
+	}
+	if err := ts.checkFit(k); err != nil {
+		return nil, err
+	}
+	if opts.MaxIterations == 0 {
+		opts.MaxIterations = 200
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = 1e-10
+	}
+
+	p := make([]float64, k)
+	copy(p, initial)
+	opts.clamp(p)
+	cost := ts.nonlinearCost(f, p)
+	if !finite(cost) {
+		return nil, ErrNonFinite
+	}
+
+	model := &NonlinearModel{
+		Function:    f,
+		Termination: TerminationIteration,
+		jacobian:    opts.Jacobian,
+		dof:         ts.Len - k,
+	}
+	lambda := 1e-3
+	trial := make([]float64, k)
+	var jtj [][]float64
+	//Termination is set once the fit converges or stalls
+	for model.Iterations < opts.MaxIterations && model.Termination == TerminationIteration {
+		model.Iterations++
+		var jtr []float64
+		jtj, jtr = ts.normalEquations(f, p, opts.Jacobian)
+		if maxAbs(jtr) == 0 {
+			model.Converged, model.Termination = true, TerminationGradient
+			break
+		}
+
+		for {
+			damped := make([][]float64, k)
+			for i := range damped {
+				damped[i] = make([]float64, k)
+				copy(damped[i], jtj[i])
+				damped[i][i] += lambda * math.Max(jtj[i][i], 1e-12)
+			}
+			step, err := solveLinear(damped, jtr)
+			if err == nil {
+				for i := range trial {
+					trial[i] = p[i] + step[i]
+				}
+				opts.clamp(trial)
+				trialCost := ts.nonlinearCost(f, trial)
+				if trialCost < cost {
+					var change, size float64
+					for i := range p {
+						change += math.Pow(trial[i]-p[i], 2)
+						size += p[i] * p[i]
+					}
+					if cost-trialCost <= opts.Tolerance*cost {
+						model.Converged, model.Termination = true, TerminationCost
+					} else if math.Sqrt(change) <= opts.Tolerance*(math.Sqrt(size)+opts.Tolerance) {
+						model.Converged, model.Termination = true, TerminationStep
+					}
+					copy(p, trial)
+					cost = trialCost
+					lambda = math.Max(lambda/10, 1e-15)
+					break
+				}
+			}
+			lambda *= 10
+			if lambda > 1e15 {
+				model.Converged, model.Termination = false, TerminationStalled
+				break
+			}
+		}
+	}
+
+	if model.Termination != TerminationStalled && model.Termination != TerminationGradient {
+		//The last accepted step moved p since JᵀJ was evaluated
+		jtj, _ = ts.normalEquations(f, p, opts.Jacobian)
+	}
+	model.Params = p
+	model.SSE = cost
+	model.Covariance, model.StdErrors = parameterCovariance(jtj, cost, ts.Len-k)
+	return model, nil
+}
+
+//Sum of squared residuals for the parameters
+func (ts *Series) nonlinearCost(f NonlinearFunc, p []float64) float64 {
+	var cost float64
+	for i := range ts.x {
+		cost += math.Pow(ts.y[i]-f(ts.x[i], p), 2)
+	}
+	if math.IsNaN(cost) {
+		return math.Inf(1)
+	}
+	return cost
+}
+
+//Returns JᵀJ and Jᵀr for the residuals r at p
+func (ts *Series) normalEquations(f NonlinearFunc, p []float64, jacobian func(float64, []float64, []float64)) ([][]float64, []float64) {
+	k := len(p)
+	jtj := newMatrix(k, k, 0)
+	jtr := make([]float64, k)
+	grad := make([]float64, k)
+	for i := range ts.x {
+		if jacobian != nil {
+			jacobian(ts.x[i], p, grad)
+		} else {
+			numericalGradient(f, ts.x[i], p, grad)
+		}
+		r := ts.y[i] - f(ts.x[i], p)
+		for a := 0; a < k; a++ {
+			jtr[a] += grad[a] * r
+			for b := 0; b < k; b++ {
+				jtj[a][b] += grad[a] * grad[b]
+			}
+		}
+	}
+	return jtj, jtr
+}
+
+//Forward difference approximation of ∂f/∂p at x
+func numericalGradient(f NonlinearFunc, x float64, p []float64, grad []float64) {
+	fx := f(x, p)
+	shifted := make([]float64, len(p))
+	copy(shifted, p)
+	for j := range p {
+		h := math.Sqrt(2.2e-16) * math.Max(math.Abs(p[j]), 1)
+		shifted[j] = p[j] + h
+		grad[j] = (f(x, shifted) - fx) / h
+		shifted[j] = p[j]
+	}
+}
+
+//Scales (JᵀJ)⁻¹ by the residual variance.  Entries are NaN when the covariance cannot be estimated.
+func parameterCovariance(jtj [][]float64, sse float64, dof int) ([][]float64, []float64) {
+	k := len(jtj)
+	stdErrors := make([]float64, k)
+	inv, err := invert(jtj)
+	if err != nil || dof <= 0 {
+		for i := range stdErrors {
+			stdErrors[i] = math.NaN()
+		}
+		return newMatrix(k, k, math.NaN()), stdErrors
+	}
+	s2 := sse / float64(dof)
+	for i := range inv {
+		for j := range inv[i] {
+			inv[i][j] *= s2
+		}
+		stdErrors[i] = math.Sqrt(inv[i][i])
+	}
+	return inv, stdErrors
+}
+
+//Restricts parameters to the bounds
+func (opts NonlinearOptions) clamp(p []float64) {
+	for i := range p {
+		if opts.Lower != nil && p[i] < opts.Lower[i] {
+			p[i] = opts.Lower[i]
+		}
+		if opts.Upper != nil && p[i] > opts.Upper[i] {
+			p[i] = opts.Upper[i]
+		}
+	}
+}
+
+func maxAbs(values []float64) float64 {
+	var max float64
+	for _, v := range values {
+		max = math.Max(max, math.Abs(v))
+	}
+	return max
+}
+
+func (m *NonlinearModel) Eval(x float64) float64 {
+	return m.Function(x, m.Params)
+}
+
+//Returns the fitted parameters
+func (m *NonlinearModel) Coefficients() []float64 {
+	return append([]float64{}, m.Params...)
+}
+
+func (m *NonlinearModel) Parameters() float64 {
+	return float64(len(m.Params))
+}
+
+func (m *NonlinearModel) Name() string {
+	return "nonlinear"
+}
+
+func (m *NonlinearModel) String() string {
+	return fmt.Sprintf("nonlinear model with parameters %v", m.Params)
+}
@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitNonlinear(t *testing.T) {
+	logistic := func(x float64, p []float64) float64 {
+		return p[0] / (1 + math.Exp(-p[1]*(x-p[2])))
+	}
+	x := make([]float64, 30)
+	y := make([]float64, 30)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = logistic(x[i], []float64{100, 0.4, 15}) + 0.5*math.Sin(float64(i))
+	}
+	s := NewSeriesFrom(x, y)
+
+	fit, err := s.FitNonlinearE(logistic, []float64{80, 1, 10}, NonlinearOptions{})
+	if err != nil {
+		t.Fatal("FitNonlinearE returned", err)
+	}
+	model := fit.Model.(*NonlinearModel)
+	if !model.Converged {
+		t.Error("Fit did not converge:", model.Termination)
+	}
+	for i, want := range []float64{100, 0.4, 15} {
+		if math.Abs(model.Params[i]-want) > 3*model.StdErrors[i] {
+			t.Error("Parameter", i, "was", model.Params[i], "±", model.StdErrors[i], ", should be", want)
+		}
+	}
+
+	//The analytic Jacobian and bounds are honoured
+	bounded, err := s.FitNonlinearE(logistic, []float64{80, 1, 10}, NonlinearOptions{
+		Jacobian: func(x float64, p []float64, grad []float64) {
+			e := math.Exp(-p[1] * (x - p[2]))
+			grad[0] = 1 / (1 + e)
+			grad[1] = p[0] * (x - p[2]) * e / math.Pow(1+e, 2)
+			grad[2] = -p[0] * p[1] * e / math.Pow(1+e, 2)
+		},
+		Lower: []float64{0, 0, 0},
+		Upper: []float64{90, 10, 30},
+	})
+	if err != nil {
+		t.Fatal("FitNonlinearE returned", err)
+	}
+	if p := bounded.Coefficients(); p[0] != 90 {
+		t.Error("Bounded capacity was", p[0], ", should be 90")
+	}
+
+	//A fit held at a bound where no step helps has stalled rather than converged
+	constant := func(x float64, p []float64) float64 { return p[0] }
+	stalled, _ := s.FitNonlinearE(constant, []float64{0}, NonlinearOptions{Lower: []float64{0}, Upper: []float64{0}})
+	if model := stalled.Model.(*NonlinearModel); model.Converged || model.Termination != TerminationStalled || model.Iterations != 1 {
+		t.Error("Stalled fit reported", model.Converged, model.Termination, "after", model.Iterations, "iterations")
+	}
+
+	if _, err := s.Slice(0, 2).FitNonlinearE(logistic, []float64{1, 1, 1}, NonlinearOptions{}); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("FitNonlinearE returned", err, ", should be", ErrInsufficientPoints)
+	}
+}
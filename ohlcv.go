@@ -0,0 +1,271 @@
+package analytics
+
+import (
+	"math"
+)
+
+//A price bar.  Start is the start of the interval for time bars, and the x of the
+//first tick otherwise; End is the x of the last tick.
+type Bar struct {
+	Start  float64
+	End    float64
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+//A series of price bars aggregated from ticks, in ascending order
+type OHLCV struct {
+	Bars []Bar
+}
+
+//Aggregates ticks into bars covering [k * interval, (k + 1) * interval).  Intervals without
+//ticks have no bar.  The volume series gives the volume of each tick, at the same x values,
+//and may be nil to count each tick as a volume of 1.  Mismatched x values are ErrInvalidArgument.
+func NewTimeBars(ticks *Series, volume *Series, interval float64) (*OHLCV, error) {
+	if !(interval > 0) || math.IsInf(interval, 0) {
+		return nil, ErrInvalidPeriod
+	}
+	return newBars(ticks, volume, func(groups []int64, volumes []float64) {
+		for i := range groups {
+			groups[i] = int64(math.Floor(ticks.x[i] / interval))
+		}
+	}, func(group int64, first int) float64 {
+		return float64(group) * interval
+	})
+}
+
+//Aggregates every n ticks into a bar.  The last bar may hold fewer ticks.
+func NewTickBars(ticks *Series, volume *Series, n int) (*OHLCV, error) {
+	if n < 1 {
+		return nil, ErrInvalidArgument
+	}
+	return newBars(ticks, volume, func(groups []int64, volumes []float64) {
+		for i := range groups {
+			groups[i] = int64(i / n)
+		}
+	}, nil)
+}
+
+//Aggregates ticks into bars which close once their volume reaches the threshold.
+//The last bar may be incomplete.
+func NewVolumeBars(ticks *Series, volume *Series, threshold float64) (*OHLCV, error) {
+	if !(threshold > 0) {
+		return nil, ErrInvalidArgument
+	}
+	return newBars(ticks, volume, func(groups []int64, volumes []float64) {
+		thresholdGroups(groups, volumes, threshold)
+	}, nil)
+}
+
+//Aggregates ticks into bars which close once their traded value, price × volume,
+//reaches the threshold.  The last bar may be incomplete.
+func NewDollarBars(ticks *Series, volume *Series, threshold float64) (*OHLCV, error) {
+	if !(threshold > 0) {
+		return nil, ErrInvalidArgument
+	}
+	return newBars(ticks, volume, func(groups []int64, volumes []float64) {
+		value := make([]float64, len(volumes))
+		for i := range value {
+			value[i] = ticks.y[i] * volumes[i]
+		}
+		thresholdGroups(groups, value, threshold)
+	}, nil)
+}
+
+//Starts a new group after the running total reaches the threshold
+func thresholdGroups(groups []int64, amounts []float64, threshold float64) {
+	var group int64
+	var total float64
+	for i := range groups {
+		groups[i] = group
+		total += amounts[i]
+		if total >= threshold {
+			group++
+			total = 0
+		}
+	}
+}
+
+//Builds a bar from each run of ticks sharing a group.  start returns the bar start for
+//a group and the index of its first tick, and defaults to the x of the first tick.
+func newBars(ticks *Series, volume *Series, group func(groups []int64, volumes []float64), start func(group int64, first int) float64) (*OHLCV, error) {
+	if ticks == nil || ticks.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if !finite(ticks.x...) || !finite(ticks.y...) {
+		return nil, ErrNonFinite
+	}
+	if !ticks.monotonic() {
+		return nil, ErrNonMonotonicX
+	}
+	volumes := make([]float64, ticks.Len)
+	if volume == nil {
+		for i := range volumes {
+			volumes[i] = 1
+		}
+	} else {
+		//Volumes are paired with ticks by index, so must be at the same x values
+		if volume.Len != ticks.Len {
+			return nil, ErrInvalidArgument
+		}
+		for i := range volume.x {
+			if volume.x[i] != ticks.x[i] {
+				return nil, ErrInvalidArgument
+			}
+		}
+		copy(volumes, volume.y)
+	}
+	groups := make([]int64, ticks.Len)
+	group(groups, volumes)
+
+	bars := &OHLCV{}
+	for first := 0; first < ticks.Len; {
+		i := first
+		for i < ticks.Len && groups[i] == groups[first] {
+			i++
+		}
+		bar := newBar(ticks.x[first:i], ticks.y[first:i], volumes[first:i])
+		if start != nil {
+			bar.Start = start(groups[first], first)
+		}
+		bars.Bars = append(bars.Bars, bar)
+		first = i
+	}
+	return bars, nil
+}
+
+//Aggregates ticks into a bar starting at the first tick.  A nil volumes counts each tick
+//as a volume of 1.  NaN prices give a NaN high and low.
+func newBar(x []float64, y []float64, volumes []float64) Bar {
+	bar := Bar{
+		Start: x[0],
+		End:   x[len(x)-1],
+		Open:  y[0],
+		High:  y[0],
+		Low:   y[0],
+		Close: y[len(y)-1],
+	}
+	for i := range y {
+		bar.High = math.Max(bar.High, y[i])
+		bar.Low = math.Min(bar.Low, y[i])
+		if volumes == nil {
+			bar.Volume++
+		} else {
+			bar.Volume += volumes[i]
+		}
+	}
+	return bar
+}
+
+//Number of bars
+func (b *OHLCV) Len() int {
+	return len(b.Bars)
+}
+
+//Returns a series of a value of each bar, at the bar's end
+func (b *OHLCV) series(value func(bar Bar) float64) *Series {
+	x := make([]float64, len(b.Bars))
+	y := make([]float64, len(b.Bars))
+	for i, bar := range b.Bars {
+		x[i], y[i] = bar.End, value(bar)
+	}
+	return NewSeriesFrom(x, y)
+}
+
+func (b *OHLCV) Open() *Series {
+	return b.series(func(bar Bar) float64 { return bar.Open })
+}
+
+func (b *OHLCV) High() *Series {
+	return b.series(func(bar Bar) float64 { return bar.High })
+}
+
+func (b *OHLCV) Low() *Series {
+	return b.series(func(bar Bar) float64 { return bar.Low })
+}
+
+func (b *OHLCV) Close() *Series {
+	return b.series(func(bar Bar) float64 { return bar.Close })
+}
+
+func (b *OHLCV) Volume() *Series {
+	return b.series(func(bar Bar) float64 { return bar.Volume })
+}
+
+//(High + Low + Close) / 3 of each bar
+func (b *OHLCV) TypicalPrice() *Series {
+	return b.series(func(bar Bar) float64 { return (bar.High + bar.Low + bar.Close) / 3 })
+}
+
+//Volume weighted average of the typical price, accumulated from the first bar.
+//Until some volume has traded it is the unweighted mean of the typical prices.
+func (b *OHLCV) VWAP() *Series {
+	var value, volume, sum, n float64
+	return b.series(func(bar Bar) float64 {
+		tp := (bar.High + bar.Low + bar.Close) / 3
+		value += tp * bar.Volume
+		volume += bar.Volume
+		sum += tp
+		n++
+		if volume == 0 {
+			return sum / n
+		}
+		return value / volume
+	})
+}
+
+//Commodity Channel Index: the typical price less its moving average over period bars,
+//divided by 0.015 times the mean absolute deviation over the same bars.
+//The first value is at the bar that completes the first period.  The index is 0 where
+//the typical price is constant over the period, so that the deviation is zero.
+func (b *OHLCV) CommodityChannelIndex(period int) (*Series, error) {
+	if period < 1 || period > len(b.Bars) {
+		return nil, ErrInvalidPeriod
+	}
+	tp := b.TypicalPrice()
+	cci := NewSeries()
+	for i := period - 1; i < tp.Len; i++ {
+		window := tp.y[i-period+1 : i+1]
+		mean := AggregateMean(window)
+		var deviation float64
+		for _, v := range window {
+			deviation += math.Abs(v - mean)
+		}
+		index := 0.0
+		if deviation > 0 {
+			index = (tp.y[i] - mean) / (0.015 * deviation / float64(period))
+		}
+		cci.Add(tp.x[i], index)
+	}
+	return cci, nil
+}
+
+//Average True Range with Wilder's smoothing.  The true range is the largest of the bar's
+//range and the distances from the previous close to its high and low.
+//The first value, at the bar that completes the first period, is the mean true range.
+func (b *OHLCV) AverageTrueRange(period int) (*Series, error) {
+	if period < 1 || period > len(b.Bars) {
+		return nil, ErrInvalidPeriod
+	}
+	atr := NewSeries()
+	var average float64
+	for i, bar := range b.Bars {
+		tr := bar.High - bar.Low
+		if i > 0 {
+			previous := b.Bars[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(bar.High-previous), math.Abs(bar.Low-previous)))
+		}
+		if i < period {
+			average += tr / float64(period)
+		} else {
+			average = (average*float64(period-1) + tr) / float64(period)
+		}
+		if i >= period-1 {
+			atr.Add(bar.End, average)
+		}
+	}
+	return atr, nil
+}
@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBars(t *testing.T) {
+	ticks := NewSeriesFrom([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{10, 12, 9, 11, 13, 14, 12, 15})
+	volume := NewSeriesFrom([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, 3, 1, 1, 2, 4, 1})
+
+	timeBars, err := NewTimeBars(ticks, volume, 3)
+	if err != nil {
+		t.Fatal("NewTimeBars returned", err)
+	}
+	want := []Bar{
+		{Start: 0, End: 2, Open: 10, High: 12, Low: 9, Close: 9, Volume: 6},
+		{Start: 3, End: 5, Open: 11, High: 14, Low: 11, Close: 14, Volume: 4},
+		{Start: 6, End: 7, Open: 12, High: 15, Low: 12, Close: 15, Volume: 5},
+	}
+	if timeBars.Len() != len(want) {
+		t.Fatal("NewTimeBars made", timeBars.Len(), "bars, should be", len(want))
+	}
+	for i := range want {
+		if timeBars.Bars[i] != want[i] {
+			t.Error("Time bar", i, "was", timeBars.Bars[i], ", should be", want[i])
+		}
+	}
+
+	tickBars, _ := NewTickBars(ticks, nil, 3)
+	if tickBars.Len() != 3 || tickBars.Bars[1].Close != 14 || tickBars.Bars[2].Volume != 2 {
+		t.Error("NewTickBars made", tickBars.Bars)
+	}
+
+	//Bars close once the threshold is reached, leaving a partial last bar
+	volumeBars, _ := NewVolumeBars(ticks, volume, 3)
+	dollarBars, _ := NewDollarBars(ticks, volume, 30)
+	for name, bars := range map[string]*OHLCV{"Volume": volumeBars, "Dollar": dollarBars} {
+		if bars.Len() != 5 || bars.Bars[4].Start != 7 || bars.Bars[3].Close != 12 {
+			t.Error(name, "bars were", bars.Bars)
+		}
+	}
+	if volumeBars.Bars[2].Volume != 4 || dollarBars.Bars[1].Open != 9 {
+		t.Error("Threshold bars were", volumeBars.Bars, dollarBars.Bars)
+	}
+
+	if _, err := NewTimeBars(ticks, NewSeries(), 3); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Mismatched volume returned", err)
+	}
+	shifted := NewSeriesFrom([]float64{1, 2, 3, 4, 5, 6, 7, 8}, []float64{1, 2, 3, 1, 1, 2, 4, 1})
+	if _, err := NewVolumeBars(ticks, shifted, 3); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Volume at different x values returned", err)
+	}
+	if _, err := NewTickBars(NewSeries(), nil, 3); !errors.Is(err, ErrEmptySeries) {
+		t.Error("Empty ticks returned", err)
+	}
+	if _, err := NewTimeBars(ticks, nil, 0); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("Zero interval returned", err)
+	}
+}
+
+func TestBarIndicators(t *testing.T) {
+	bars := &OHLCV{Bars: []Bar{
+		{End: 2, Open: 10, High: 12, Low: 9, Close: 9, Volume: 6},
+		{End: 5, Open: 11, High: 14, Low: 11, Close: 14, Volume: 4},
+		{End: 7, Open: 12, High: 15, Low: 12, Close: 15, Volume: 5},
+	}}
+
+	cci, err := bars.CommodityChannelIndex(3)
+	if err != nil {
+		t.Fatal("CommodityChannelIndex returned", err)
+	}
+	if x, y := cci.Point(0); cci.Len != 1 || x != 7 || math.Abs(y-500.0/7) > 1e-9 {
+		t.Error("CommodityChannelIndex was", x, y, ", should be", 500.0/7)
+	}
+
+	atr, _ := bars.AverageTrueRange(2)
+	if atr.Len != 2 || atr.y[0] != 4 || atr.y[1] != 3.5 || atr.x[0] != 5 {
+		t.Error("AverageTrueRange was", atr.x, atr.y)
+	}
+
+	vwap := bars.VWAP()
+	for i, v := range []float64{10, 11.2, 182.0 / 15} {
+		if math.Abs(vwap.y[i]-v) > 1e-12 {
+			t.Error("VWAP", i, "was", vwap.y[i], ", should be", v)
+		}
+	}
+
+	if _, err := bars.CommodityChannelIndex(4); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("Period longer than the bars returned", err)
+	}
+	if _, err := bars.AverageTrueRange(0); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("AverageTrueRange with no period returned", err)
+	}
+
+	//A constant typical price has no deviation, and the index is defined as 0
+	flat := &OHLCV{Bars: []Bar{{End: 1, Open: 5, High: 6, Low: 4, Close: 5}, {End: 2, Open: 5, High: 6, Low: 4, Close: 5}}}
+	if cci, _ := flat.CommodityChannelIndex(2); cci.Len != 1 || cci.y[0] != 0 {
+		t.Error("CommodityChannelIndex of flat bars was", cci.y)
+	}
+	//Without volume the VWAP is the mean typical price
+	if vwap := flat.VWAP(); vwap.y[0] != 5 || vwap.y[1] != 5 {
+		t.Error("VWAP without volume was", vwap.y)
+	}
+}
+
+func TestCommonChannelIndex(t *testing.T) {
+	ticks := NewSeriesFrom([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{10, 12, 9, 11, 13, 14, 12, 15})
+	cci := ticks.CommonChannelIndex(2, 3)
+	//Each value is at the end of its own period
+	if cci.Len != 3 || cci.x[0] != 3 || cci.x[1] != 5 || cci.x[2] != 7 {
+		t.Error("CommonChannelIndex x values were", cci.x)
+	}
+
+	//A first period starting before the first tick keeps the ticks it contains
+	if cci := ticks.CommonChannelIndex(3, 3); cci.Len != 3 || cci.x[0] != 1 || cci.x[1] != 4 || cci.x[2] != 7 {
+		t.Error("CommonChannelIndex from before the first tick had x values", cci.x)
+	}
+
+	//Fewer than 3 periods have no index
+	if cci := ticks.CommonChannelIndex(4, 2); cci.Len != 0 {
+		t.Error("CommonChannelIndex of 2 periods was", cci.y)
+	}
+	//A NaN tick propagates rather than panicking
+	ticks = NewSeriesFrom([]float64{0, 1, 2, 3, 4, 5}, []float64{1, 2, 3, math.NaN(), 4, 5})
+	if cci := ticks.CommonChannelIndex(1, 5); cci.Len != 5 || !math.IsNaN(cci.y[4]) {
+		t.Error("CommonChannelIndex with a NaN tick was", cci.y)
+	}
+	//A constant price gives an index of 0
+	ticks = NewSeriesFrom([]float64{0, 1, 2, 3, 4, 5}, []float64{3, 3, 3, 3, 3, 3})
+	if cci := ticks.CommonChannelIndex(1, 5); cci.Len != 5 || cci.y[4] != 0 {
+		t.Error("CommonChannelIndex of a constant price was", cci.y)
+	}
+}
@@ -0,0 +1,315 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+//Peak line shapes for FitPeaks
+const (
+	PeakGaussian = iota
+	PeakLorentzian
+	PeakPseudoVoigt //Mixture η Lorentzian + (1 - η) Gaussian with a shared width
+)
+
+//Options for FitPeaks
+type PeakOptions struct {
+	//One of the Peak shape constants
+	Shape int
+	//Number of peaks to fit.  When zero, every local maximum at least 10% as high
+	//as the largest is fitted.
+	Peaks int
+	//Order of the polynomial baseline, 0 for a constant (default) or -1 for no baseline
+	Baseline int
+}
+
+//A fitted peak.  FWHM is the full width at half maximum and Area the integral of the
+//peak above the baseline.  Eta is the Lorentzian fraction of a pseudo-Voigt peak.
+type Peak struct {
+	Height   float64
+	Position float64
+	FWHM     float64
+	Area     float64
+	Eta      float64
+}
+
+//A sum of peaks on a polynomial baseline, fitted by nonlinear least squares.
+//The embedded NonlinearModel holds the convergence diagnostics and covariance of the
+//parameters: height, position and FWHM (and η for pseudo-Voigt) of each peak in the
+//order of Peaks, followed by the baseline coefficients in x scaled to [-1, 1].
+type PeaksModel struct {
+	*NonlinearModel
+	Shape    int
+	Peaks    []Peak //In ascending order of position
+	Baseline *PolynomialModel
+}
+
+//4 ln 2, relating the FWHM of a Gaussian to its variance
+const gaussianFWHM = 2.772588722239781
+
+func gaussianPeak(x float64, height float64, position float64, fwhm float64) float64 {
+	d := (x - position) / fwhm
+	return height * math.Exp(-gaussianFWHM*d*d)
+}
+
+func lorentzianPeak(x float64, height float64, position float64, fwhm float64) float64 {
+	d := (x - position) / fwhm
+	return height / (1 + 4*d*d)
+}
+
+//Fits a sum of Gaussian, Lorentzian or pseudo-Voigt peaks plus a polynomial baseline.
+//Initial peaks are seeded one at a time at the highest point of the data left
+//unexplained by the baseline and the peaks seeded so far.
+func (ts *Series) FitPeaks(opts PeakOptions) FitParameters {
+	return mustFit(ts.FitPeaksE(opts))
+}
+
+//As FitPeaks, but returns an error for invalid input, or ErrNoFit when fewer peaks are found than requested.
+//A fit that fails to converge is not an error; check Converged on the returned model.
+func (ts *Series) FitPeaksE(opts PeakOptions) (params FitParameters, err error) {
+	if opts.Shape < PeakGaussian || opts.Shape > PeakPseudoVoigt || opts.Peaks < 0 || opts.Baseline < -1 {
+		return params, ErrInvalidArgument
+	}
+	perPeak := 3
+	if opts.Shape == PeakPseudoVoigt {
+		perPeak = 4
+	}
+	nbase := opts.Baseline + 1
+	min := perPeak*opts.Peaks + nbase
+	if opts.Peaks == 0 {
+		min += perPeak
+	}
+	if err = ts.checkFit(min); err != nil {
+		return
+	}
+	center := (ts.x[0] + ts.x[ts.Len-1]) / 2
+	scale := (ts.x[ts.Len-1] - ts.x[0]) / 2
+	if scale == 0 {
+		return params, ErrSingularMatrix
+	}
+
+	initial := ts.seedPeaks(opts, perPeak, nbase)
+	if initial == nil {
+		return params, ErrNoFit
+	}
+	npeaks := (len(initial) - nbase) / perPeak
+	shape := opts.Shape
+	f := func(x float64, p []float64) float64 {
+		var y float64
+		for k := 0; k < npeaks; k++ {
+			q := p[k*perPeak:]
+			switch shape {
+			case PeakGaussian:
+				y += gaussianPeak(x, q[0], q[1], q[2])
+			case PeakLorentzian:
+				y += lorentzianPeak(x, q[0], q[1], q[2])
+			case PeakPseudoVoigt:
+				y += q[3]*lorentzianPeak(x, q[0], q[1], q[2]) + (1-q[3])*gaussianPeak(x, q[0], q[1], q[2])
+			}
+		}
+		t := (x - center) / scale
+		var base float64
+		for j := len(p) - 1; j >= npeaks*perPeak; j-- {
+			base = base*t + p[j]
+		}
+		return y + base
+	}
+
+	//Widths stay positive and pseudo-Voigt fractions in [0, 1]
+	inf := math.Inf(1)
+	lower := make([]float64, len(initial))
+	upper := make([]float64, len(initial))
+	for i := range lower {
+		lower[i], upper[i] = -inf, inf
+	}
+	for k := 0; k < npeaks; k++ {
+		lower[k*perPeak+2] = 1e-9 * scale
+		if shape == PeakPseudoVoigt {
+			lower[k*perPeak+3], upper[k*perPeak+3] = 0, 1
+		}
+	}
+	nonlinear, err := ts.levenbergMarquardt(f, initial, NonlinearOptions{Lower: lower, Upper: upper, MaxIterations: 500})
+	if err != nil {
+		return
+	}
+
+	sortPeaks(nonlinear, npeaks, perPeak)
+	baseline := append([]float64{}, nonlinear.Params[npeaks*perPeak:]...)
+	if nbase == 0 {
+		baseline = []float64{0}
+	}
+	model := &PeaksModel{
+		NonlinearModel: nonlinear,
+		Shape:          shape,
+		Peaks:          make([]Peak, npeaks),
+		Baseline:       newScaledPolynomial(baseline, center, scale, ts.x[0]-1, 0),
+	}
+	for k := range model.Peaks {
+		q := nonlinear.Params[k*perPeak:]
+		peak := Peak{Height: q[0], Position: q[1], FWHM: q[2]}
+		if shape == PeakLorentzian {
+			peak.Eta = 1
+		} else if shape == PeakPseudoVoigt {
+			peak.Eta = q[3]
+		}
+		//Areas of the unit height Gaussian and Lorentzian are FWHM √(π / 4 ln 2) and FWHM π / 2
+		peak.Area = peak.Height * peak.FWHM * (peak.Eta*math.Pi/2 + (1-peak.Eta)*math.Sqrt(math.Pi/gaussianFWHM))
+		model.Peaks[k] = peak
+	}
+
+	params = FitParameters{
+		Type:  FitTypePeaks,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Reorders the parameter blocks of the peaks by position, with their standard errors
+//and covariances, so that they stay in the same order as PeaksModel.Peaks
+func sortPeaks(model *NonlinearModel, npeaks int, perPeak int) {
+	order := make([]int, npeaks)
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return model.Params[order[i]*perPeak+1] < model.Params[order[j]*perPeak+1]
+	})
+	//The parameter index each new index is taken from
+	from := make([]int, len(model.Params))
+	for i := range from {
+		from[i] = i
+	}
+	for k, old := range order {
+		for j := 0; j < perPeak; j++ {
+			from[k*perPeak+j] = old*perPeak + j
+		}
+	}
+	permute := func(v []float64) []float64 {
+		if v == nil {
+			return nil
+		}
+		permuted := make([]float64, len(v))
+		for i := range v {
+			permuted[i] = v[from[i]]
+		}
+		return permuted
+	}
+	model.Params = permute(model.Params)
+	model.StdErrors = permute(model.StdErrors)
+	if model.Covariance != nil {
+		covariance := make([][]float64, len(from))
+		for i := range covariance {
+			covariance[i] = permute(model.Covariance[from[i]])
+		}
+		model.Covariance = covariance
+	}
+}
+
+//Returns initial peak parameters followed by the baseline coefficients in scaled x,
+//or nil when no peak is found
+func (ts *Series) seedPeaks(opts PeakOptions, perPeak int, nbase int) []float64 {
+	//Start from a baseline through the ends of the data
+	base := make([]float64, nbase)
+	if nbase == 1 {
+		base[0] = ts.Min
+	} else if nbase > 1 {
+		first, last := ts.y[0], ts.y[ts.Len-1]
+		base[0], base[1] = (first+last)/2, (last-first)/2
+	}
+	residuals := make([]float64, ts.Len)
+	for i := range ts.x {
+		residuals[i] = ts.y[i]
+		if nbase > 0 {
+			residuals[i] -= base[0]
+		}
+		if nbase > 1 {
+			residuals[i] -= base[1] * (2*(ts.x[i]-ts.x[0])/(ts.x[ts.Len-1]-ts.x[0]) - 1)
+		}
+	}
+
+	count := opts.Peaks
+	if count == 0 {
+		highest := maxFloat(residuals)
+		for i := 1; i < ts.Len-1; i++ {
+			if residuals[i] > residuals[i-1] && residuals[i] >= residuals[i+1] && residuals[i] >= 0.1*highest {
+				count++
+			}
+		}
+		//Keep at least one point per parameter
+		if limit := (ts.Len - nbase) / perPeak; count > limit {
+			count = limit
+		}
+	}
+
+	var initial []float64
+	for k := 0; k < count; k++ {
+		top := 0
+		for i := range residuals {
+			if residuals[i] > residuals[top] {
+				top = i
+			}
+		}
+		height := residuals[top]
+		if !(height > 0) {
+			return nil
+		}
+		fwhm := ts.halfMaximumWidth(residuals, top)
+		if !(fwhm > 0) {
+			fwhm = (ts.x[ts.Len-1] - ts.x[0]) / float64(ts.Len-1)
+		}
+		initial = append(initial, height, ts.x[top], fwhm)
+		if perPeak == 4 {
+			initial = append(initial, 0.5)
+		}
+		for i := range residuals {
+			residuals[i] -= gaussianPeak(ts.x[i], height, ts.x[top], fwhm)
+		}
+	}
+	if initial == nil {
+		return nil
+	}
+	return append(initial, base...)
+}
+
+//Estimates the FWHM of the peak in values at index top from where it falls to half its height
+func (ts *Series) halfMaximumWidth(values []float64, top int) float64 {
+	half := values[top] / 2
+	crossing := func(step int) (float64, bool) {
+		for i := top + step; i >= 0 && i < len(values); i += step {
+			if values[i] <= half {
+				//Interpolate between the points either side of half maximum
+				j := i - step
+				return math.Abs(ts.x[j] + (ts.x[i]-ts.x[j])*(values[j]-half)/(values[j]-values[i]) - ts.x[top]), true
+			}
+		}
+		return 0, false
+	}
+	left, lok := crossing(-1)
+	right, rok := crossing(1)
+	switch {
+	case lok && rok:
+		return left + right
+	case lok:
+		return 2 * left
+	case rok:
+		return 2 * right
+	}
+	return (ts.x[ts.Len-1] - ts.x[0]) / 2
+}
+
+func (m *PeaksModel) Name() string {
+	return "peaks"
+}
+
+func (m *PeaksModel) String() string {
+	shapes := []string{"gaussian", "lorentzian", "pseudo-voigt"}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s peaks on baseline %v", len(m.Peaks), shapes[m.Shape], m.Baseline)
+	for _, peak := range m.Peaks {
+		fmt.Fprintf(&b, "; height %g at %g with FWHM %g and area %g", peak.Height, peak.Position, peak.FWHM, peak.Area)
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitPeaks(t *testing.T) {
+	//Two overlapping peaks on a sloping baseline
+	x := make([]float64, 200)
+	y := make([]float64, 200)
+	for i := range x {
+		x[i] = float64(i) / 10
+		y[i] = 2 + 0.1*x[i] + gaussianPeak(x[i], 10, 7, 1.5) + lorentzianPeak(x[i], 6, 11, 2) + 0.02*math.Sin(float64(3*i))
+	}
+	s := NewSeriesFrom(x, y)
+
+	fit, err := s.FitPeaksE(PeakOptions{Shape: PeakPseudoVoigt, Peaks: 2, Baseline: 1})
+	if err != nil {
+		t.Fatal("FitPeaksE returned", err)
+	}
+	model := fit.Model.(*PeaksModel)
+	if !model.Converged || fit.Report.RMSE > 0.05 {
+		t.Error("Fit did not converge:", model.Termination, fit.Report.RMSE)
+	}
+	want := []Peak{
+		{Height: 10, Position: 7, FWHM: 1.5, Eta: 0, Area: 10 * 1.5 * math.Sqrt(math.Pi/(4*math.Ln2))},
+		{Height: 6, Position: 11, FWHM: 2, Eta: 1, Area: 6 * math.Pi},
+	}
+	for i, peak := range model.Peaks {
+		if math.Abs(peak.Height-want[i].Height) > 0.1 || math.Abs(peak.Position-want[i].Position) > 0.02 ||
+			math.Abs(peak.FWHM-want[i].FWHM) > 0.05 || math.Abs(peak.Eta-want[i].Eta) > 0.05 ||
+			math.Abs(peak.Area-want[i].Area) > 0.02*want[i].Area {
+			t.Error("Peak", i, "was", peak, ", should be", want[i])
+		}
+	}
+	if b := model.Baseline.Eval(10); math.Abs(b-3) > 0.05 {
+		t.Error("Baseline at 10 was", b)
+	}
+
+	//Parameters follow the order of Peaks, though the taller, later peak is seeded first
+	for i := range x {
+		y[i] = gaussianPeak(x[i], 4, 5, 1) + gaussianPeak(x[i], 10, 14, 2)
+	}
+	ordered, err := NewSeriesFrom(x, y).FitPeaksE(PeakOptions{Peaks: 2, Baseline: -1})
+	if err != nil {
+		t.Fatal("FitPeaksE returned", err)
+	}
+	peaks := ordered.Model.(*PeaksModel)
+	for k, peak := range peaks.Peaks {
+		if peaks.Params[3*k+1] != peak.Position || peaks.Params[3*k] != peak.Height {
+			t.Error("Parameters of peak", k, "were", peaks.Params[3*k:3*k+3], ", should be", peak)
+		}
+		if peaks.Covariance != nil && peaks.StdErrors[3*k+1] != math.Sqrt(peaks.Covariance[3*k+1][3*k+1]) {
+			t.Error("Standard error of peak", k, "does not match its covariance")
+		}
+	}
+	if peaks.Peaks[0].Position > peaks.Peaks[1].Position || ordered.Report.RMSE > 1e-6 {
+		t.Error("Peaks were", peaks.Peaks, "with RMSE", ordered.Report.RMSE)
+	}
+
+	//The number of peaks is found automatically
+	auto, err := s.FitPeaksE(PeakOptions{Baseline: 1})
+	if err != nil || len(auto.Model.(*PeaksModel).Peaks) != 2 {
+		t.Error("Automatic FitPeaksE returned", auto.Model, err)
+	}
+
+	if _, err := s.FitPeaksE(PeakOptions{Shape: 5}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("FitPeaksE with an unknown shape returned", err)
+	}
+	flat := NewSeriesFrom([]float64{1, 2, 3, 4, 5}, []float64{1, 1, 1, 1, 1})
+	if _, err := flat.FitPeaksE(PeakOptions{Peaks: 1}); !errors.Is(err, ErrNoFit) {
+		t.Error("FitPeaksE of a flat series returned", err)
+	}
+}
+
+func TestFitGaussianParabolic(t *testing.T) {
+	x := make([]float64, 21)
+	y := make([]float64, 21)
+	for i := range x {
+		x[i] = 1000 + float64(i)
+		y[i] = 5 * math.Exp(-math.Pow((x[i]-1010)/4, 2))
+	}
+	params, err := NewSeriesFrom(x, y).FitGaussianParabolicE()
+	if err != nil {
+		t.Fatal("FitGaussianParabolicE returned", err)
+	}
+	gaussian := params[1].Model.(*GaussianModel)
+	if math.Abs(gaussian.Position+gaussian.XOffset-1010) > 0.5 {
+		t.Error("Gaussian position was", gaussian.Position+gaussian.XOffset)
+	}
+}
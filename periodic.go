@@ -0,0 +1,251 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+//y = Amplitude * sin(2π Frequency (x - XOffset) + Phase) + Offset
+//
+//Amplitude is non-negative and Phase is in [0, 2π).  The embedded NonlinearModel
+//holds the convergence diagnostics and the covariance, and its Params, which Eval uses,
+//are amplitude, frequency, phase and offset.
+type SinusoidModel struct {
+	*NonlinearModel
+	xoffset float64
+}
+
+func (m *SinusoidModel) Amplitude() float64 {
+	return m.Params[0]
+}
+
+//Cycles per unit of x
+func (m *SinusoidModel) Frequency() float64 {
+	return m.Params[1]
+}
+
+func (m *SinusoidModel) Phase() float64 {
+	return m.Params[2]
+}
+
+func (m *SinusoidModel) Offset() float64 {
+	return m.Params[3]
+}
+
+//The x at which the phase is measured, the first x of the fitted series
+func (m *SinusoidModel) XOffset() float64 {
+	return m.xoffset
+}
+
+//Fits a single sinusoid.  The initial frequency is the peak of a least squares
+//periodogram, so the series does not need to be evenly spaced.
+func (ts *Series) FitSinusoid() FitParameters {
+	return mustFit(ts.FitSinusoidE())
+}
+
+//As FitSinusoid, but returns an error for invalid input.
+//A fit that fails to converge is not an error; check Converged on the returned model.
+func (ts *Series) FitSinusoidE() (params FitParameters, err error) {
+	if err = ts.checkFit(4); err != nil {
+		return
+	}
+	xoffset := ts.x[0]
+	initial, err := ts.periodogramPeak(xoffset)
+	if err != nil {
+		return
+	}
+
+	f := func(x float64, p []float64) float64 {
+		return p[0]*math.Sin(2*math.Pi*p[1]*(x-xoffset)+p[2]) + p[3]
+	}
+	inf := math.Inf(1)
+	opts := NonlinearOptions{
+		Jacobian: func(x float64, p []float64, grad []float64) {
+			t := x - xoffset
+			s, c := math.Sincos(2*math.Pi*p[1]*t + p[2])
+			grad[0] = s
+			grad[1] = p[0] * c * 2 * math.Pi * t
+			grad[2] = p[0] * c
+			grad[3] = 1
+		},
+		Lower: []float64{0, 0, -inf, -inf},
+		Upper: []float64{inf, inf, inf, inf},
+	}
+	nonlinear, err := ts.levenbergMarquardt(f, initial, opts)
+	if err != nil {
+		return
+	}
+	//Shifting the phase by whole cycles changes neither the curve nor the covariance
+	nonlinear.Params[2] = math.Mod(nonlinear.Params[2], 2*math.Pi)
+	if nonlinear.Params[2] < 0 {
+		nonlinear.Params[2] += 2 * math.Pi
+	}
+
+	params = FitParameters{
+		Type: FitTypeSinusoid,
+		Model: &SinusoidModel{
+			NonlinearModel: nonlinear,
+			xoffset:        xoffset,
+		},
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Scans frequencies from one cycle over the series to the Nyquist frequency of the
+//mean spacing, fitting a sin(θ) + b cos(θ) + c at each.  Returns amplitude, frequency,
+//phase and offset at the frequency with the smallest sum of squared residuals.
+func (ts *Series) periodogramPeak(xoffset float64) ([]float64, error) {
+	span := ts.x[ts.Len-1] - ts.x[0]
+	if span == 0 {
+		return nil, ErrSingularMatrix
+	}
+	lowest := 1 / span
+	highest := float64(ts.Len-1) / (2 * span)
+	step := lowest / 4
+
+	var best []float64
+	bestSSE := math.Inf(1)
+	basis := make([]float64, 3)
+	for frequency := lowest; frequency <= highest+step/2; frequency += step {
+		xtx := newMatrix(3, 3, 0)
+		xty := make([]float64, 3)
+		var yty float64
+		for i := range ts.x {
+			basis[0], basis[1] = math.Sincos(2 * math.Pi * frequency * (ts.x[i] - xoffset))
+			basis[2] = 1
+			for a := range basis {
+				xty[a] += basis[a] * ts.y[i]
+				for b := range basis {
+					xtx[a][b] += basis[a] * basis[b]
+				}
+			}
+			yty += ts.y[i] * ts.y[i]
+		}
+		coef, err := solveLinear(xtx, xty)
+		if err != nil {
+			continue
+		}
+		sse := yty
+		for a := range coef {
+			sse -= coef[a] * xty[a]
+		}
+		if sse < bestSSE {
+			bestSSE = sse
+			best = []float64{math.Hypot(coef[0], coef[1]), frequency, math.Atan2(coef[1], coef[0]), coef[2]}
+		}
+	}
+	if best == nil {
+		return nil, ErrSingularMatrix
+	}
+	return best, nil
+}
+
+func (m *SinusoidModel) Name() string {
+	return "sinusoid"
+}
+
+func (m *SinusoidModel) String() string {
+	return fmt.Sprintf("y = %gsin(2π%g(x - %g) + %g) + %g", m.Amplitude(), m.Frequency(), m.XOffset(), m.Phase(), m.Offset())
+}
+
+//y = Offset + Σ Cosine[k-1] cos(2πkx / Period) + Sine[k-1] sin(2πkx / Period), for k = 1 to the number of harmonics
+type FourierModel struct {
+	Period float64
+	Offset float64
+	Cosine []float64
+	Sine   []float64
+	uncertainty
+}
+
+//Fits a Fourier series with the given period and number of harmonics by linear least squares,
+//e.g. a period of 24 hours with 3 harmonics for a daily cycle.
+func (ts *Series) FitFourier(period float64, harmonics int) FitParameters {
+	return mustFit(ts.FitFourierE(period, harmonics))
+}
+
+//As FitFourier, but returns an error for invalid input.
+//ErrSingularMatrix is returned when the x values cannot resolve the harmonics.
+func (ts *Series) FitFourierE(period float64, harmonics int) (params FitParameters, err error) {
+	if !(period > 0) || math.IsInf(period, 0) || harmonics < 1 {
+		return params, ErrInvalidArgument
+	}
+	if err = ts.checkFit(2*harmonics + 1); err != nil {
+		return
+	}
+
+	design := make([][]float64, ts.Len)
+	for i := range ts.x {
+		design[i] = make([]float64, 2*harmonics+1)
+		fourierBasis(ts.x[i], period, design[i])
+	}
+	solution, err := solveLeastSquares(design, ts.y, nil)
+	if err != nil {
+		return
+	}
+
+	model := &FourierModel{
+		Period: period,
+		Offset: solution.coef[0],
+		Cosine: make([]float64, harmonics),
+		Sine:   make([]float64, harmonics),
+	}
+	for k := 0; k < harmonics; k++ {
+		model.Cosine[k] = solution.coef[2*k+1]
+		model.Sine[k] = solution.coef[2*k+2]
+	}
+	model.cov = solution.unscaledCovariance()
+	params = FitParameters{
+		Type:  FitTypeFourier,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	model.setResidualVariance(params.Report)
+	return
+}
+
+//Fills basis with 1, cos(θ), sin(θ), cos(2θ), sin(2θ), ... where θ = 2πx / period
+func fourierBasis(x float64, period float64, basis []float64) {
+	theta := 2 * math.Pi * math.Mod(x, period) / period
+	basis[0] = 1
+	for k := 1; 2*k < len(basis); k++ {
+		basis[2*k], basis[2*k-1] = math.Sincos(float64(k) * theta)
+	}
+}
+
+func (m *FourierModel) Eval(x float64) float64 {
+	basis := make([]float64, 2*len(m.Cosine)+1)
+	fourierBasis(x, m.Period, basis)
+	y := m.Offset
+	for k := range m.Cosine {
+		y += m.Cosine[k]*basis[2*k+1] + m.Sine[k]*basis[2*k+2]
+	}
+	return y
+}
+
+//Returns the offset followed by the cosine and sine coefficient of each harmonic
+func (m *FourierModel) Coefficients() []float64 {
+	coefficients := []float64{m.Offset}
+	for k := range m.Cosine {
+		coefficients = append(coefficients, m.Cosine[k], m.Sine[k])
+	}
+	return coefficients
+}
+
+func (m *FourierModel) Parameters() float64 {
+	return float64(2*len(m.Cosine) + 1)
+}
+
+func (m *FourierModel) Name() string {
+	return "fourier"
+}
+
+func (m *FourierModel) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "y = %g", m.Offset)
+	for k := range m.Cosine {
+		fmt.Fprintf(&b, " + %gcos(2π%dx/%g) + %gsin(2π%dx/%g)", m.Cosine[k], k+1, m.Period, m.Sine[k], k+1, m.Period)
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitSinusoid(t *testing.T) {
+	//Unevenly spaced samples of 3 sin(2π 0.1 x + 1) + 5
+	x := make([]float64, 60)
+	y := make([]float64, 60)
+	for i := range x {
+		x[i] = float64(i) + 0.3*math.Sin(float64(i*i))
+		y[i] = 3*math.Sin(2*math.Pi*0.1*x[i]+1) + 5 + 0.05*math.Cos(float64(7*i))
+	}
+	fit, err := NewSeriesFrom(x, y).FitSinusoidE()
+	if err != nil {
+		t.Fatal("FitSinusoidE returned", err)
+	}
+	model := fit.Model.(*SinusoidModel)
+	if !model.Converged {
+		t.Error("Fit did not converge:", model.Termination)
+	}
+	if math.Abs(model.Amplitude()-3) > 0.05 || math.Abs(model.Frequency()-0.1) > 1e-3 || math.Abs(model.Offset()-5) > 0.05 {
+		t.Error("Fitted", model)
+	}
+	//The phase is measured from the first x
+	want := math.Mod(1+2*math.Pi*0.1*x[0], 2*math.Pi)
+	if math.Abs(model.Phase()-want) > 0.05 {
+		t.Error("Phase was", model.Phase(), ", should be", want)
+	}
+	if e := Extrapolate(fit, 100); math.Abs(e-(3*math.Sin(2*math.Pi*10+1)+5)) > 0.2 {
+		t.Error("Extrapolated value was", e)
+	}
+}
+
+func TestFitFourier(t *testing.T) {
+	//Hourly samples of a daily cycle with two harmonics
+	x := make([]float64, 72)
+	y := make([]float64, 72)
+	for i := range x {
+		x[i] = float64(i)
+		theta := 2 * math.Pi * x[i] / 24
+		y[i] = 10 + 4*math.Cos(theta) - 2*math.Sin(theta) + math.Sin(2*theta)
+	}
+	fit, err := NewSeriesFrom(x, y).FitFourierE(24, 2)
+	if err != nil {
+		t.Fatal("FitFourierE returned", err)
+	}
+	for i, want := range []float64{10, 4, -2, 0, 1} {
+		if c := fit.Coefficients()[i]; math.Abs(c-want) > 1e-9 {
+			t.Error("Coefficient", i, "was", c, ", should be", want)
+		}
+	}
+	if e := Extrapolate(fit, 24*10+6); math.Abs(e-(10-2-0)) > 1e-9 {
+		t.Error("Extrapolated value was", e)
+	}
+	if _, _, err := ConfidenceInterval(fit, 80, 0.95); err != nil {
+		t.Error("ConfidenceInterval returned", err)
+	}
+
+	if _, err := NewSeriesFrom(x, y).FitFourierE(0, 2); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("FitFourierE with zero period returned", err)
+	}
+	if _, err := NewSeriesFrom(x[:4], y[:4]).FitFourierE(24, 2); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("FitFourierE of 4 points returned", err)
+	}
+}
@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+//A straight line fitted to the points with x in [Start, End]
+type Segment struct {
+	Start     float64
+	End       float64
+	Gradient  float64
+	Intercept float64 //y = Gradient * x + Intercept
+	Points    int
+}
+
+//Contiguous line segments, which need not meet at the breakpoints.
+//Between two segments the nearer segment is used, and beyond the data
+//the first or last segment is extended.
+type PiecewiseLinearModel struct {
+	Segments []Segment
+}
+
+//Fits up to maxSegments line segments, with the breakpoints placed by dynamic programming
+//to minimise the sum of squared residuals.  The number of segments is chosen by BIC,
+//counting a gradient and intercept per segment and each breakpoint as parameters.
+//Each segment covers at least 3 points.
+func (ts *Series) FitPiecewiseLinear(maxSegments int) FitParameters {
+	return mustFit(ts.FitPiecewiseLinearE(maxSegments))
+}
+
+//As FitPiecewiseLinear, but returns an error for invalid input.
+func (ts *Series) FitPiecewiseLinearE(maxSegments int) (params FitParameters, err error) {
+	if maxSegments < 1 {
+		return params, ErrInvalidArgument
+	}
+	if err = ts.checkFit(2); err != nil {
+		return
+	}
+	const minPoints = 3
+	limit := ts.Len / minPoints
+	if limit < 1 {
+		limit = 1
+	}
+	if maxSegments > limit {
+		maxSegments = limit
+	}
+	sums := ts.segmentSums()
+
+	//cost[k][j] is the least SSE of k+1 segments covering the first j points, and
+	//start[k][j] the first point of the last of those segments
+	n := ts.Len
+	cost := newMatrix(maxSegments, n+1, math.Inf(1))
+	start := make([][]int, maxSegments)
+	for k := range start {
+		start[k] = make([]int, n+1)
+	}
+	for j := 1; j <= n; j++ {
+		cost[0][j] = sums.sse(0, j)
+	}
+	for k := 1; k < maxSegments; k++ {
+		for j := (k + 1) * minPoints; j <= n; j++ {
+			for i := k * minPoints; i <= j-minPoints; i++ {
+				if c := cost[k-1][i] + sums.sse(i, j); c < cost[k][j] {
+					cost[k][j], start[k][j] = c, i
+				}
+			}
+		}
+	}
+
+	//Floor the SSE so that exact fits are not rewarded without limit
+	floor := 1e-12 * sums.sse(0, n)
+	if floor == 0 {
+		floor = 1e-300
+	}
+	best, bestScore := 0, math.Inf(1)
+	for k := 0; k < maxSegments; k++ {
+		if math.IsInf(cost[k][n], 1) {
+			continue
+		}
+		p := float64(3*(k+1) - 1)
+		score := float64(n)*math.Log(math.Max(cost[k][n], floor)/float64(n)) + p*math.Log(float64(n))
+		if score < bestScore {
+			best, bestScore = k, score
+		}
+	}
+
+	model := &PiecewiseLinearModel{Segments: make([]Segment, best+1)}
+	end := n
+	for k := best; k >= 0; k-- {
+		i := start[k][end]
+		gradient, intercept := sums.line(i, end)
+		model.Segments[k] = Segment{
+			Start:     ts.x[i],
+			End:       ts.x[end-1],
+			Gradient:  gradient,
+			Intercept: intercept - gradient*sums.xoffset + sums.yoffset,
+			Points:    end - i,
+		}
+		end = i
+	}
+
+	params = FitParameters{
+		Type:  FitTypePiecewiseLinear,
+		Model: model,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Prefix sums of x, y, x², xy and y², offset by the first x and the mean y for precision
+type segmentSums struct {
+	x, y, xx, xy, yy []float64
+	xoffset          float64
+	yoffset          float64
+}
+
+func (ts *Series) segmentSums() *segmentSums {
+	s := &segmentSums{
+		x:       make([]float64, ts.Len+1),
+		y:       make([]float64, ts.Len+1),
+		xx:      make([]float64, ts.Len+1),
+		xy:      make([]float64, ts.Len+1),
+		yy:      make([]float64, ts.Len+1),
+		xoffset: ts.x[0],
+		yoffset: ts.Mean,
+	}
+	for i := range ts.x {
+		x := ts.x[i] - s.xoffset
+		y := ts.y[i] - s.yoffset
+		s.x[i+1] = s.x[i] + x
+		s.y[i+1] = s.y[i] + y
+		s.xx[i+1] = s.xx[i] + x*x
+		s.xy[i+1] = s.xy[i] + x*y
+		s.yy[i+1] = s.yy[i] + y*y
+	}
+	return s
+}
+
+//Returns the centred sums of squares and products of points i to j-1
+func (s *segmentSums) centred(i int, j int) (sxx float64, sxy float64, syy float64) {
+	n := float64(j - i)
+	sx, sy := s.x[j]-s.x[i], s.y[j]-s.y[i]
+	sxx = s.xx[j] - s.xx[i] - sx*sx/n
+	sxy = s.xy[j] - s.xy[i] - sx*sy/n
+	syy = s.yy[j] - s.yy[i] - sy*sy/n
+	return
+}
+
+//SSE of the least squares line through points i to j-1
+func (s *segmentSums) sse(i int, j int) float64 {
+	sxx, sxy, syy := s.centred(i, j)
+	if sxx <= 0 {
+		return math.Max(syy, 0)
+	}
+	return math.Max(syy-sxy*sxy/sxx, 0)
+}
+
+//Least squares line through points i to j-1, in offset coordinates.
+//Points sharing a single x give a horizontal line through their mean.
+func (s *segmentSums) line(i int, j int) (gradient float64, intercept float64) {
+	n := float64(j - i)
+	sxx, sxy, _ := s.centred(i, j)
+	if sxx > 0 {
+		gradient = sxy / sxx
+	}
+	intercept = (s.y[j]-s.y[i])/n - gradient*(s.x[j]-s.x[i])/n
+	return
+}
+
+func (m *PiecewiseLinearModel) Eval(x float64) float64 {
+	k := 0
+	for k < len(m.Segments)-1 && x > (m.Segments[k].End+m.Segments[k+1].Start)/2 {
+		k++
+	}
+	return m.Segments[k].Gradient*x + m.Segments[k].Intercept
+}
+
+//Returns the gradient and intercept of each segment, followed by the breakpoints
+func (m *PiecewiseLinearModel) Coefficients() []float64 {
+	coefficients := make([]float64, 0, 3*len(m.Segments)-1)
+	for _, segment := range m.Segments {
+		coefficients = append(coefficients, segment.Gradient, segment.Intercept)
+	}
+	for _, segment := range m.Segments[1:] {
+		coefficients = append(coefficients, segment.Start)
+	}
+	return coefficients
+}
+
+//Each segment's gradient and intercept, and each breakpoint
+func (m *PiecewiseLinearModel) Parameters() float64 {
+	return float64(3*len(m.Segments) - 1)
+}
+
+func (m *PiecewiseLinearModel) Name() string {
+	return "piecewise linear"
+}
+
+func (m *PiecewiseLinearModel) String() string {
+	lines := make([]string, len(m.Segments))
+	for k, segment := range m.Segments {
+		lines[k] = fmt.Sprintf("y = %gx + %g for x in [%g, %g]", segment.Gradient, segment.Intercept, segment.Start, segment.End)
+	}
+	return strings.Join(lines, "; ")
+}
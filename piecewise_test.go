@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitPiecewiseLinear(t *testing.T) {
+	//Rising, flat then falling, with a little noise
+	x := make([]float64, 60)
+	y := make([]float64, 60)
+	for i := range x {
+		x[i] = float64(i)
+		switch {
+		case i < 20:
+			y[i] = 2 * x[i]
+		case i < 35:
+			y[i] = 40
+		default:
+			y[i] = 40 - 3*(x[i]-35)
+		}
+		y[i] += 0.1 * math.Sin(float64(5*i))
+	}
+	s := NewSeriesFrom(x, y)
+
+	fit, err := s.FitPiecewiseLinearE(5)
+	if err != nil {
+		t.Fatal("FitPiecewiseLinearE returned", err)
+	}
+	model := fit.Model.(*PiecewiseLinearModel)
+	if len(model.Segments) != 3 {
+		t.Fatal("Fitted", model)
+	}
+	for i, want := range []Segment{{0, 19, 2, 0, 20}, {20, 34, 0, 40, 15}, {35, 59, -3, 145, 25}} {
+		got := model.Segments[i]
+		if got.Start != want.Start || got.End != want.End || got.Points != want.Points ||
+			math.Abs(got.Gradient-want.Gradient) > 0.02 || math.Abs(got.Intercept-want.Intercept) > 0.5 {
+			t.Error("Segment", i, "was", got, ", should be", want)
+		}
+	}
+	if e := Extrapolate(fit, 70); math.Abs(e-(40-3*35)) > 0.5 {
+		t.Error("Extrapolated value was", e)
+	}
+	if len(fit.Coefficients()) != 8 || fit.Report.NumParameters != 8 {
+		t.Error("Coefficients were", fit.Coefficients())
+	}
+
+	//A single line is not split
+	line, _ := NewSeriesFrom(x, x).FitPiecewiseLinearE(5)
+	if segments := line.Model.(*PiecewiseLinearModel).Segments; len(segments) != 1 {
+		t.Error("Straight line was split into", segments)
+	}
+
+	if _, err := s.FitPiecewiseLinearE(0); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("FitPiecewiseLinearE with no segments returned", err)
+	}
+}
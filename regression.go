@@ -0,0 +1,221 @@
+package analytics
+
+import (
+	"math"
+)
+
+//Options for RegressWithOptions.  Penalties apply to the predictors standardised to zero
+//mean and unit variance, so that they are penalised equally whatever their units.
+//The intercept is never penalised.
+type RegressionOptions struct {
+	//L2 penalty λ Σ β² (ridge regression)
+	Ridge float64
+	//L1 penalty λ Σ |β| (lasso), which sets the coefficients of unhelpful predictors to zero.
+	//Combined with Ridge this is the elastic net.
+	Lasso float64
+}
+
+//The result of a multiple linear regression.  Coefficients, StdErrors, TStats and PValues
+//hold the intercept followed by one entry per predictor.  Standard errors, t statistics
+//and p-values are NaN for penalised regressions, whose coefficients are biased.
+type Regression struct {
+	Coefficients     []float64
+	StdErrors        []float64
+	TStats           []float64
+	PValues          []float64 //Two sided, for the hypothesis that the coefficient is zero
+	N                int       //Number of aligned points
+	DegreesOfFreedom int
+	RSquared         float64
+	AdjustedRSquared float64
+	StandardError    float64   //Residual standard error
+	Residuals        []float64 //In the order of the target's aligned points
+}
+
+//Regresses the target on the predictor series by ordinary least squares.
+//The series are aligned on x: only x values present in every series are used.
+//Returns ErrSingularMatrix when the predictors are collinear.
+func Regress(target *Series, predictors ...*Series) (*Regression, error) {
+	return RegressWithOptions(RegressionOptions{}, target, predictors...)
+}
+
+//As Regress, with optional ridge and lasso penalties.
+func RegressWithOptions(opts RegressionOptions, target *Series, predictors ...*Series) (*Regression, error) {
+	if len(predictors) == 0 || !(opts.Ridge >= 0) || !(opts.Lasso >= 0) || !finite(opts.Ridge, opts.Lasso) {
+		return nil, ErrInvalidArgument
+	}
+	if target == nil || target.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	design, y, err := alignPredictors(target, predictors)
+	if err != nil {
+		return nil, err
+	}
+	n, k := len(y), len(predictors)
+	if n < k+2 {
+		return nil, ErrInsufficientPoints
+	}
+
+	r := &Regression{
+		StdErrors:        make([]float64, k+1),
+		TStats:           make([]float64, k+1),
+		PValues:          make([]float64, k+1),
+		N:                n,
+		DegreesOfFreedom: n - k - 1,
+		Residuals:        make([]float64, n),
+	}
+	var cov [][]float64
+	if opts.Ridge == 0 && opts.Lasso == 0 {
+		solution, err := solveLeastSquares(design, y, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.Coefficients = solution.coef
+		cov = solution.unscaledCovariance()
+	} else {
+		r.Coefficients = penalisedRegression(design, y, opts)
+	}
+
+	var mean, sse, sst float64
+	for i := range y {
+		mean += y[i] / float64(n)
+	}
+	for i := range y {
+		var fitted float64
+		for j := range design[i] {
+			fitted += design[i][j] * r.Coefficients[j]
+		}
+		r.Residuals[i] = y[i] - fitted
+		sse += r.Residuals[i] * r.Residuals[i]
+		sst += (y[i] - mean) * (y[i] - mean)
+	}
+	dof := float64(r.DegreesOfFreedom)
+	r.RSquared = 1 - sse/sst
+	r.AdjustedRSquared = 1 - (1-r.RSquared)*float64(n-1)/dof
+	r.StandardError = math.Sqrt(sse / dof)
+	for j := range r.Coefficients {
+		if cov == nil {
+			r.StdErrors[j], r.TStats[j], r.PValues[j] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		r.StdErrors[j] = r.StandardError * math.Sqrt(cov[j][j])
+		r.TStats[j] = r.Coefficients[j] / r.StdErrors[j]
+		r.PValues[j] = studentTPValue(r.TStats[j], dof)
+	}
+	return r, nil
+}
+
+//Returns the design matrix, with a leading column of ones, and the target values
+//at the x values shared by every series
+func alignPredictors(target *Series, predictors []*Series) ([][]float64, []float64, error) {
+	values := make([]map[float64]float64, len(predictors))
+	for j, p := range predictors {
+		if p == nil || p.Len == 0 {
+			return nil, nil, ErrEmptySeries
+		}
+		values[j] = make(map[float64]float64, p.Len)
+		for i := range p.x {
+			if _, ok := values[j][p.x[i]]; !ok {
+				values[j][p.x[i]] = p.y[i]
+			}
+		}
+	}
+
+	var design [][]float64
+	var y []float64
+	for i, x := range target.x {
+		row := make([]float64, len(predictors)+1)
+		row[0] = 1
+		aligned := true
+		for j := range values {
+			if row[j+1], aligned = values[j][x]; !aligned {
+				break
+			}
+		}
+		if !aligned {
+			continue
+		}
+		if !finite(row...) || !finite(target.y[i]) {
+			return nil, nil, ErrNonFinite
+		}
+		design = append(design, row)
+		y = append(y, target.y[i])
+	}
+	return design, y, nil
+}
+
+//Minimises Σ r² + Ridge Σ β² + Lasso Σ |β| over standardised predictors by cyclic
+//coordinate descent, returning the intercept and coefficients on the original scale
+func penalisedRegression(design [][]float64, y []float64, opts RegressionOptions) []float64 {
+	n, k := len(y), len(design[0])-1
+	means := make([]float64, k+1)
+	scales := make([]float64, k+1)
+	for i := range y {
+		means[0] += y[i] / float64(n)
+		for j := 1; j <= k; j++ {
+			means[j] += design[i][j] / float64(n)
+		}
+	}
+	for j := 1; j <= k; j++ {
+		for i := range y {
+			scales[j] += math.Pow(design[i][j]-means[j], 2) / float64(n)
+		}
+		scales[j] = math.Sqrt(scales[j])
+	}
+	//Standardised predictors z have Σ z² = n
+	z := newMatrix(n, k+1, 0)
+	residuals := make([]float64, n)
+	for i := range y {
+		for j := 1; j <= k; j++ {
+			if scales[j] > 0 {
+				z[i][j] = (design[i][j] - means[j]) / scales[j]
+			}
+		}
+		residuals[i] = y[i] - means[0]
+	}
+
+	beta := make([]float64, k+1)
+	for iteration := 0; iteration < 10000; iteration++ {
+		var change, size float64
+		for j := 1; j <= k; j++ {
+			if scales[j] == 0 {
+				continue
+			}
+			var rho float64
+			for i := range y {
+				rho += z[i][j] * (residuals[i] + z[i][j]*beta[j])
+			}
+			//Soft thresholding by the lasso penalty
+			next := math.Max(math.Abs(rho)-opts.Lasso/2, 0) * math.Copysign(1, rho) / (float64(n) + opts.Ridge)
+			if delta := next - beta[j]; delta != 0 {
+				for i := range y {
+					residuals[i] -= z[i][j] * delta
+				}
+				change = math.Max(change, math.Abs(delta))
+				beta[j] = next
+			}
+			size = math.Max(size, math.Abs(beta[j]))
+		}
+		if change <= 1e-12*(size+1e-12) {
+			break
+		}
+	}
+
+	coefficients := make([]float64, k+1)
+	coefficients[0] = means[0]
+	for j := 1; j <= k; j++ {
+		if scales[j] > 0 {
+			coefficients[j] = beta[j] / scales[j]
+			coefficients[0] -= coefficients[j] * means[j]
+		}
+	}
+	return coefficients
+}
+
+//Returns the fitted value for one value of each predictor
+func (r *Regression) Predict(values ...float64) float64 {
+	y := r.Coefficients[0]
+	for j := range values {
+		y += r.Coefficients[j+1] * values[j]
+	}
+	return y
+}
@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRegress(t *testing.T) {
+	//Load depends on temperature and hour of day, but not on noise
+	var x, load, temperature, hour, noise []float64
+	for i := 0; i < 100; i++ {
+		x = append(x, float64(i))
+		temperature = append(temperature, 15+10*math.Sin(float64(i)/7))
+		hour = append(hour, float64(i%24))
+		noise = append(noise, math.Cos(float64(i*i)))
+		load = append(load, 50+2*temperature[i]-0.5*hour[i]+0.3*math.Sin(float64(13*i)))
+	}
+	//The target has extra points which are dropped when aligning
+	target := NewSeriesFrom(append(x, 100, 101), append(load, 0, 0))
+
+	r, err := Regress(target, NewSeriesFrom(x, temperature), NewSeriesFrom(x, hour), NewSeriesFrom(x, noise))
+	if err != nil {
+		t.Fatal("Regress returned", err)
+	}
+	if r.N != 100 || r.DegreesOfFreedom != 96 || r.RSquared < 0.99 {
+		t.Error("Regression was", r)
+	}
+	for j, want := range []float64{50, 2, -0.5, 0} {
+		if math.Abs(r.Coefficients[j]-want) > 3*r.StdErrors[j] {
+			t.Error("Coefficient", j, "was", r.Coefficients[j], "±", r.StdErrors[j], ", should be", want)
+		}
+	}
+	if r.PValues[1] > 1e-10 || r.PValues[3] < 0.01 {
+		t.Error("P-values were", r.PValues)
+	}
+	if p := r.Predict(20, 12, 0); math.Abs(p-84) > 0.2 {
+		t.Error("Prediction was", p)
+	}
+
+	//The lasso removes the irrelevant predictor and ridge shrinks the coefficients
+	lasso, _ := RegressWithOptions(RegressionOptions{Lasso: 20}, target, NewSeriesFrom(x, temperature), NewSeriesFrom(x, hour), NewSeriesFrom(x, noise))
+	if lasso.Coefficients[3] != 0 || math.Abs(lasso.Coefficients[1]-2) > 0.05 || !math.IsNaN(lasso.PValues[1]) {
+		t.Error("Lasso coefficients were", lasso.Coefficients)
+	}
+	ridge, _ := RegressWithOptions(RegressionOptions{Ridge: 1000}, target, NewSeriesFrom(x, temperature))
+	ols, _ := Regress(target, NewSeriesFrom(x, temperature))
+	if !(math.Abs(ridge.Coefficients[1]) < math.Abs(ols.Coefficients[1])) {
+		t.Error("Ridge coefficient", ridge.Coefficients[1], "was not shrunk from", ols.Coefficients[1])
+	}
+
+	if _, err := Regress(target, NewSeriesFrom(x, temperature), NewSeriesFrom(x, temperature)); !errors.Is(err, ErrSingularMatrix) {
+		t.Error("Regress on collinear predictors returned", err)
+	}
+	if _, err := Regress(target); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Regress without predictors returned", err)
+	}
+}
@@ -0,0 +1,222 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+//Reduces the values in a bucket to a single value
+type Aggregator func(values []float64) float64
+
+//Fill policies for resampled buckets without points, and for missing points when joining series
+const (
+	FillNaN      = iota
+	FillPrevious //The value of the previous bucket
+	FillLinear   //Linear interpolation between the neighbouring non-empty buckets
+	FillZero
+)
+
+//Bucket labels
+const (
+	LabelLeft  = iota //Label each bucket with its start
+	LabelRight        //Label each bucket with its end
+)
+
+//Bucket alignment for Resample.  Buckets cover [Origin + k * interval, Origin + (k + 1) * interval),
+//so the zero value aligns buckets to multiples of the interval.
+type ResampleOptions struct {
+	Label  int //One of the Label constants
+	Origin float64
+}
+
+//Bucket alignment for TimeSeries.Resample.  The zero Origin is the Unix epoch.
+type TimeResampleOptions struct {
+	Label  int //One of the Label constants
+	Origin time.Time
+}
+
+//The maximum number of buckets Resample will create
+const maxBuckets = 1 << 26
+
+//Aggregates a bucket to its first value
+func AggregateFirst(values []float64) float64 {
+	return values[0]
+}
+
+//Aggregates a bucket to its last value
+func AggregateLast(values []float64) float64 {
+	return values[len(values)-1]
+}
+
+//Aggregates a bucket to its smallest value
+func AggregateMin(values []float64) float64 {
+	return minFloat(values)
+}
+
+//Aggregates a bucket to its largest value
+func AggregateMax(values []float64) float64 {
+	return maxFloat(values)
+}
+
+//Aggregates a bucket to the sum of its values
+func AggregateSum(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+//Aggregates a bucket to the mean of its values
+func AggregateMean(values []float64) float64 {
+	return AggregateSum(values) / float64(len(values))
+}
+
+//Aggregates a bucket to the number of points in it
+func AggregateCount(values []float64) float64 {
+	return float64(len(values))
+}
+
+//Aggregates a bucket to the median of its values
+func AggregateMedian(values []float64) float64 {
+	return median(values)
+}
+
+//Buckets the series into regular intervals of x, reducing the points in each bucket with the
+//aggregator, e.g. AggregateMean.  Every bucket from the first point to the last is returned,
+//with empty buckets filled according to the Fill policy.
+func (ts *Series) Resample(interval float64, aggregator Aggregator, fill int, opts ResampleOptions) *Series {
+	resampled, err := ts.ResampleE(interval, aggregator, fill, opts)
+	if err != nil {
+		panic(err)
+	}
+	return resampled
+}
+
+//As Resample, but returns an error for invalid input.
+func (ts *Series) ResampleE(interval float64, aggregator Aggregator, fill int, opts ResampleOptions) (*Series, error) {
+	if !(interval > 0) || math.IsInf(interval, 0) {
+		return nil, ErrInvalidPeriod
+	}
+	if aggregator == nil || !finite(opts.Origin) || !validResample(fill, opts.Label) {
+		return nil, ErrInvalidArgument
+	}
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if !finite(ts.x...) {
+		return nil, ErrNonFinite
+	}
+	if !ts.monotonic() {
+		return nil, ErrNonMonotonicX
+	}
+
+	first := math.Floor((ts.x[0] - opts.Origin) / interval)
+	last := math.Floor((ts.x[ts.Len-1] - opts.Origin) / interval)
+	if last-first >= maxBuckets {
+		return nil, ErrInvalidPeriod
+	}
+	buckets := make([]int, ts.Len)
+	for i := range ts.x {
+		buckets[i] = int(math.Floor((ts.x[i]-opts.Origin)/interval) - first)
+	}
+	y := resampleBuckets(buckets, ts.y, int(last-first)+1, aggregator, fill)
+
+	x := make([]float64, len(y))
+	for k := range x {
+		x[k] = opts.Origin + (first+float64(k+opts.Label))*interval
+	}
+	return NewSeriesFrom(x, y), nil
+}
+
+//Buckets the time series into regular intervals, as Series.Resample
+func (ts *TimeSeries) Resample(interval time.Duration, aggregator Aggregator, fill int, opts TimeResampleOptions) (*TimeSeries, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if aggregator == nil || !validResample(fill, opts.Label) {
+		return nil, ErrInvalidArgument
+	}
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if !ts.series.monotonic() {
+		return nil, ErrNonMonotonicX
+	}
+	origin := opts.Origin
+	if origin.IsZero() {
+		origin = time.Unix(0, 0)
+	}
+
+	//Whole buckets since the origin, rounding down before it
+	index := func(t time.Time) int64 {
+		k := int64(t.Sub(origin) / interval)
+		if t.Before(origin.Add(time.Duration(k) * interval)) {
+			k--
+		}
+		return k
+	}
+	first, last := index(ts.times[0]), index(ts.times[ts.Len-1])
+	if last-first >= maxBuckets {
+		return nil, ErrInvalidPeriod
+	}
+	buckets := make([]int, ts.Len)
+	for i, t := range ts.times {
+		buckets[i] = int(index(t) - first)
+	}
+	y := resampleBuckets(buckets, ts.series.y, int(last-first)+1, aggregator, fill)
+
+	times := make([]time.Time, len(y))
+	for k := range times {
+		times[k] = origin.Add(time.Duration(first+int64(k+opts.Label)) * interval)
+	}
+	return NewTimeSeriesFrom(times, y)
+}
+
+func validResample(fill int, label int) bool {
+	return validFill(fill) && label >= LabelLeft && label <= LabelRight
+}
+
+func validFill(fill int) bool {
+	return fill >= FillNaN && fill <= FillZero
+}
+
+//Aggregates the values in each of count buckets, given the bucket of each value in
+//ascending order, and fills the empty buckets
+func resampleBuckets(buckets []int, values []float64, count int, aggregator Aggregator, fill int) []float64 {
+	y := make([]float64, count)
+	filled := make([]bool, count)
+	for start := 0; start < len(values); {
+		end := start
+		for end < len(values) && buckets[end] == buckets[start] {
+			end++
+		}
+		y[buckets[start]] = aggregator(values[start:end])
+		filled[buckets[start]] = true
+		start = end
+	}
+
+	//The first and last buckets always hold points
+	previous := 0
+	for k := range y {
+		if filled[k] {
+			previous = k
+			continue
+		}
+		switch fill {
+		case FillNaN:
+			y[k] = math.NaN()
+		case FillPrevious:
+			y[k] = y[previous]
+		case FillLinear:
+			next := k + 1
+			for !filled[next] {
+				next++
+			}
+			y[k] = y[previous] + (y[next]-y[previous])*float64(k-previous)/float64(next-previous)
+		case FillZero:
+			y[k] = 0
+		}
+	}
+	return y
+}
@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestResample(t *testing.T) {
+	//Irregular ticks with a gap between 20 and 40
+	s := NewSeriesFrom([]float64{3, 4, 9, 12, 18, 41, 47, 52}, []float64{1, 2, 3, 4, 5, 6, 7, 8})
+
+	cases := []struct {
+		aggregator Aggregator
+		fill       int
+		want       []float64
+	}{
+		{AggregateMean, FillNaN, []float64{2.5, 5, math.NaN(), 6.5, 8}},
+		{AggregateSum, FillZero, []float64{10, 5, 0, 13, 8}},
+		{AggregateLast, FillPrevious, []float64{4, 5, 5, 7, 8}},
+		{AggregateFirst, FillLinear, []float64{1, 5, 5.5, 6, 8}},
+		{AggregateCount, FillZero, []float64{4, 1, 0, 2, 1}},
+		{AggregateMax, FillNaN, []float64{4, 5, math.NaN(), 7, 8}},
+		{AggregateMin, FillNaN, []float64{1, 5, math.NaN(), 6, 8}},
+		{AggregateMedian, FillNaN, []float64{2.5, 5, math.NaN(), 6.5, 8}},
+	}
+	for n, c := range cases {
+		resampled, err := s.ResampleE(12, c.aggregator, c.fill, ResampleOptions{Origin: 2})
+		if err != nil {
+			t.Fatal("ResampleE returned", err)
+		}
+		for k := range c.want {
+			x, y := resampled.Point(k)
+			if x != 2+12*float64(k) || !(y == c.want[k] || math.IsNaN(y) && math.IsNaN(c.want[k])) {
+				t.Error("Case", n, "bucket", k, "was", x, y, ", should be", c.want[k])
+			}
+		}
+	}
+
+	//Right labels and the default origin
+	right := s.Resample(10, AggregateCount, FillZero, ResampleOptions{Label: LabelRight})
+	if x, _ := right.Point(0); right.Len != 6 || x != 10 {
+		t.Error("Right labelled buckets started at", x, "with", right.Len, "buckets")
+	}
+
+	//Time series are bucketed by duration from the epoch
+	start := time.Date(2024, 1, 1, 9, 59, 0, 0, time.UTC)
+	ticks, _ := NewTimeSeriesFrom([]time.Time{start, start.Add(2 * time.Minute), start.Add(3 * time.Hour)}, []float64{1, 2, 3})
+	hourly, err := ticks.Resample(time.Hour, AggregateSum, FillZero, TimeResampleOptions{})
+	if err != nil || hourly.Len != 4 {
+		t.Fatal("Hourly resampling returned", hourly, err)
+	}
+	if first, y := hourly.Point(0); !first.Equal(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)) || y != 1 {
+		t.Error("First hour was", first, y)
+	}
+	if sums := hourly.Values(); sums[1] != 2 || sums[2] != 0 || sums[3] != 3 {
+		t.Error("Hourly sums were", hourly.Values())
+	}
+
+	if _, err := s.ResampleE(0, AggregateMean, FillNaN, ResampleOptions{}); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("ResampleE with no interval returned", err)
+	}
+	if _, err := s.ResampleE(1, AggregateMean, 7, ResampleOptions{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("ResampleE with an unknown fill returned", err)
+	}
+
+	//Unordered times are reported rather than indexing outside the buckets
+	times := []time.Time{start, start.Add(2 * time.Hour), start.Add(time.Hour)}
+	unordered := &TimeSeries{times: times, series: NewSeriesFrom([]float64{TimeToX(times[0]), TimeToX(times[1]), TimeToX(times[2])}, []float64{1, 2, 3})}
+	unordered.updateStats()
+	if _, err := unordered.Resample(time.Hour, AggregateMean, FillNaN, TimeResampleOptions{}); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("Resampling unordered times returned", err)
+	}
+}
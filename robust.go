@@ -0,0 +1,282 @@
+package analytics
+
+import (
+	"math"
+	"math/rand"
+)
+
+//Options for FitRANSAC
+type RANSACOptions struct {
+	//Points within Threshold of a candidate line are counted as inliers
+	Threshold float64
+	//Number of candidate lines tried, defaults to 100
+	Iterations int
+	//Seed for the random selection of candidate lines
+	Seed int64
+}
+
+//The number of pairwise slopes above which FitTheilSen samples pairs, about 2000 points
+const maxTheilSenPairs = 1 << 21
+
+//Fits a line with the Theil–Sen estimator: the median of the slopes between every pair
+//of points, and the median intercept.  Up to 29% of the points can be outliers
+//without affecting the fit.  Points with residuals more than 3 robust standard
+//deviations from the line are marked as outliers in the returned mask.
+//Above about 2000 points the median is taken over a fixed, reproducible random sample
+//of 2^21 pairs, which bounds the time and memory used.
+func (ts *Series) FitTheilSen() (FitParameters, []bool) {
+	params, inliers, err := ts.FitTheilSenE()
+	if err != nil {
+		panic(err)
+	}
+	return params, inliers
+}
+
+//As FitTheilSen, but returns an error for invalid input.
+func (ts *Series) FitTheilSenE() (params FitParameters, inliers []bool, err error) {
+	if err = ts.checkFit(2); err != nil {
+		return
+	}
+	slopes := ts.theilSenSlopes()
+	if len(slopes) == 0 {
+		return params, nil, ErrSingularMatrix
+	}
+	gradient := median(slopes)
+	intercepts := make([]float64, ts.Len)
+	for i := range ts.x {
+		intercepts[i] = ts.y[i] - gradient*ts.x[i]
+	}
+
+	params = ts.robustLinear(gradient, median(intercepts), nil)
+	inliers = ts.robustInliers(params.Model, 3)
+	return
+}
+
+//The slopes between every pair of points with distinct x, or a sample of maxTheilSenPairs of them
+func (ts *Series) theilSenSlopes() []float64 {
+	if pairs := ts.Len * (ts.Len - 1) / 2; pairs <= maxTheilSenPairs {
+		slopes := make([]float64, 0, pairs)
+		for i := range ts.x {
+			for j := i + 1; j < ts.Len; j++ {
+				if ts.x[j] != ts.x[i] {
+					slopes = append(slopes, (ts.y[j]-ts.y[i])/(ts.x[j]-ts.x[i]))
+				}
+			}
+		}
+		return slopes
+	}
+	random := rand.New(rand.NewSource(1))
+	slopes := make([]float64, 0, maxTheilSenPairs)
+	//Pairs with equal x are redrawn, up to a limit in case most x values are repeated
+	for tries := 0; tries < 4*maxTheilSenPairs && len(slopes) < maxTheilSenPairs; tries++ {
+		i, j := random.Intn(ts.Len), random.Intn(ts.Len)
+		if ts.x[j] != ts.x[i] {
+			slopes = append(slopes, (ts.y[j]-ts.y[i])/(ts.x[j]-ts.x[i]))
+		}
+	}
+	return slopes
+}
+
+//Fits a line by Huber M-estimation using iteratively reweighted least squares.
+//Residuals larger than k robust standard deviations are down-weighted; k defaults
+//to 1.345 when zero, giving 95% efficiency for normally distributed errors.
+//Points outside k robust standard deviations are marked as outliers in the returned mask.
+func (ts *Series) FitHuber(k float64) (FitParameters, []bool) {
+	params, inliers, err := ts.FitHuberE(k)
+	if err != nil {
+		panic(err)
+	}
+	return params, inliers
+}
+
+//As FitHuber, but returns an error for invalid input.
+func (ts *Series) FitHuberE(k float64) (params FitParameters, inliers []bool, err error) {
+	if k < 0 || !finite(k) {
+		return params, nil, ErrInvalidArgument
+	}
+	if k == 0 {
+		k = 1.345
+	}
+	if err = ts.checkFit(2); err != nil {
+		return
+	}
+
+	weights := make([]float64, ts.Len)
+	for i := range weights {
+		weights[i] = 1
+	}
+	line, err := checkFitValues(ts.fitLinear(weights))
+	if err != nil {
+		return
+	}
+	residuals := make([]float64, ts.Len)
+	for iteration := 0; iteration < 50; iteration++ {
+		for i := range ts.x {
+			residuals[i] = ts.y[i] - line.Eval(ts.x[i])
+		}
+		scale := robustScale(residuals)
+		if scale == 0 {
+			break
+		}
+		for i := range weights {
+			u := math.Abs(residuals[i]) / scale
+			weights[i] = 1
+			if u > k {
+				weights[i] = k / u
+			}
+		}
+		next, err := checkFitValues(ts.fitLinear(weights))
+		if err != nil {
+			break
+		}
+		previous := line.Coefficients()
+		line = next
+		current := line.Coefficients()
+		if math.Abs(current[0]-previous[0]) <= 1e-10*math.Abs(previous[0]) &&
+			math.Abs(current[1]-previous[1]) <= 1e-10*math.Abs(previous[1]) {
+			break
+		}
+	}
+
+	fitted := line.Model.(*LinearModel)
+	params = ts.robustLinear(fitted.Gradient, fitted.Eval(0), weights)
+	inliers = ts.robustInliers(params.Model, k)
+	return
+}
+
+//Fits a line by random sample consensus.  Lines through random pairs of points are
+//scored by the number of points within the threshold, and the line with the most
+//inliers is refitted by least squares to those inliers.
+func (ts *Series) FitRANSAC(opts RANSACOptions) (FitParameters, []bool) {
+	params, inliers, err := ts.FitRANSACE(opts)
+	if err != nil {
+		panic(err)
+	}
+	return params, inliers
+}
+
+//As FitRANSAC, but returns an error for invalid input, or ErrNoFit when
+//no line has at least two inliers.
+func (ts *Series) FitRANSACE(opts RANSACOptions) (params FitParameters, inliers []bool, err error) {
+	if !(opts.Threshold > 0) || opts.Iterations < 0 {
+		return params, nil, ErrInvalidArgument
+	}
+	if opts.Iterations == 0 {
+		opts.Iterations = 100
+	}
+	if err = ts.checkFit(2); err != nil {
+		return
+	}
+
+	random := rand.New(rand.NewSource(opts.Seed))
+	bestCount := 0
+	var bestError float64
+	var best []float64
+	weights := make([]float64, ts.Len)
+	for iteration := 0; iteration < opts.Iterations; iteration++ {
+		i, j := random.Intn(ts.Len), random.Intn(ts.Len)
+		if ts.x[i] == ts.x[j] {
+			continue
+		}
+		gradient := (ts.y[j] - ts.y[i]) / (ts.x[j] - ts.x[i])
+		intercept := ts.y[i] - gradient*ts.x[i]
+		count := 0
+		var sse float64
+		for n := range ts.x {
+			r := math.Abs(ts.y[n] - (gradient*ts.x[n] + intercept))
+			if r <= opts.Threshold {
+				count++
+				sse += r * r
+			}
+		}
+		if count > bestCount || (count == bestCount && sse < bestError) {
+			bestCount, bestError = count, sse
+			best = []float64{gradient, intercept}
+		}
+	}
+	if bestCount < 2 {
+		return params, nil, ErrNoFit
+	}
+
+	for n := range ts.x {
+		weights[n] = 0
+		if math.Abs(ts.y[n]-(best[0]*ts.x[n]+best[1])) <= opts.Threshold {
+			weights[n] = 1
+		}
+	}
+	line, err := checkFitValues(ts.fitLinear(weights))
+	if err != nil {
+		//The inliers share an x value; keep the candidate line
+		params = ts.robustLinear(best[0], best[1], weights)
+	} else {
+		fitted := line.Model.(*LinearModel)
+		params = ts.robustLinear(fitted.Gradient, fitted.Eval(0), weights)
+	}
+
+	inliers = make([]bool, ts.Len)
+	for n := range ts.x {
+		inliers[n] = math.Abs(ts.y[n]-params.Eval(ts.x[n])) <= opts.Threshold
+	}
+	return
+}
+
+//Builds a linear fit for y = gradient * x + intercept, using the same offsets as FitLinear.
+//The correlation is weighted by the final robust weights, if any.
+func (ts *Series) robustLinear(gradient float64, intercept float64, weights []float64) FitParameters {
+	xoffset := ts.x[0] - 1
+	yoffset := ts.Min - 1
+	var sumw, sumx, sumy float64
+	for i := range ts.x {
+		w := weightAt(weights, i)
+		sumw += w
+		sumx += w * ts.x[i]
+		sumy += w * ts.y[i]
+	}
+	var sxy, sxx, syy float64
+	for i := range ts.x {
+		w := weightAt(weights, i)
+		dx := ts.x[i] - sumx/sumw
+		dy := ts.y[i] - sumy/sumw
+		sxy += w * dx * dy
+		sxx += w * dx * dx
+		syy += w * dy * dy
+	}
+
+	params := FitParameters{
+		Type: FitTypeLinear,
+		Model: &LinearModel{
+			Gradient:    gradient,
+			Intercept:   intercept + gradient*xoffset - yoffset,
+			Correlation: sxy / math.Sqrt(sxx*syy),
+			XOffset:     xoffset,
+			YOffset:     yoffset,
+		},
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return params
+}
+
+//Marks points within cutoff robust standard deviations of the model
+func (ts *Series) robustInliers(m Model, cutoff float64) []bool {
+	residuals := make([]float64, ts.Len)
+	for i := range ts.x {
+		residuals[i] = ts.y[i] - m.Eval(ts.x[i])
+	}
+	scale := robustScale(residuals)
+	inliers := make([]bool, ts.Len)
+	for i := range residuals {
+		inliers[i] = math.Abs(residuals[i]) <= cutoff*scale || math.Abs(residuals[i]) <= 1e-9*(math.Abs(ts.y[i])+1)
+	}
+	return inliers
+}
+
+//Standard deviation estimated from the median absolute deviation, which is
+//unaffected by outliers
+func robustScale(residuals []float64) float64 {
+	m := median(residuals)
+	deviations := make([]float64, len(residuals))
+	for i := range residuals {
+		deviations[i] = math.Abs(residuals[i] - m)
+	}
+	return 1.4826 * median(deviations)
+}
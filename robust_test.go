@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRobustLinearFits(t *testing.T) {
+	x := make([]float64, 20)
+	y := make([]float64, 20)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = 2*x[i] + 1 + 0.01*math.Sin(float64(i))
+	}
+	y[7] = 500
+	y[15] = -300
+	s := NewSeriesFrom(x, y)
+
+	theilSen, tsInliers := s.FitTheilSen()
+	huber, huberInliers := s.FitHuber(0)
+	ransac, ransacInliers := s.FitRANSAC(RANSACOptions{Threshold: 0.1, Seed: 1})
+
+	fits := map[string]FitParameters{"Theil-Sen": theilSen, "Huber": huber, "RANSAC": ransac}
+	masks := map[string][]bool{"Theil-Sen": tsInliers, "Huber": huberInliers, "RANSAC": ransacInliers}
+	for name, fit := range fits {
+		gradient := fit.Model.(*LinearModel).Gradient
+		if math.Abs(gradient-2) > 0.01 {
+			t.Error(name, "gradient was", gradient, ", should be 2")
+		}
+		if v := Extrapolate(fit, 30); math.Abs(v-61) > 0.2 {
+			t.Error(name, "extrapolated to", v, ", should be 61")
+		}
+		for i, inlier := range masks[name] {
+			if inlier == (i == 7 || i == 15) {
+				t.Error(name, "inlier mask was", inlier, "for point", i)
+			}
+		}
+	}
+
+	if _, _, err := s.FitRANSACE(RANSACOptions{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("FitRANSACE returned", err, ", should be", ErrInvalidArgument)
+	}
+
+	//Long series take the median over a sample of pairs
+	x = make([]float64, 5000)
+	y = make([]float64, 5000)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = 2*x[i] + 1 + math.Sin(float64(i))
+		if i%10 == 0 {
+			y[i] = 0
+		}
+	}
+	long, _ := NewSeriesFrom(x, y).FitTheilSen()
+	if gradient := long.Model.(*LinearModel).Gradient; math.Abs(gradient-2) > 1e-3 {
+		t.Error("Sampled Theil-Sen gradient was", gradient, ", should be 2")
+	}
+}
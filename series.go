@@ -135,6 +135,14 @@ func (ts *Series) Last(n int) *Series {
 	return NewSeriesFrom(x, y)
 }
 
+//As Last, but returns ErrOutOfRange when n exceeds the length of the series.
+func (ts *Series) LastE(n int) (*Series, error) {
+	if n < 0 || n > ts.Len {
+		return nil, ErrOutOfRange
+	}
+	return ts.Last(n), nil
+}
+
 //Extracts the values from the series as a 1 dimensional slice
 func (ts *Series) ToValues(length int, offset int) (x []float64, y []float64) {
 	x = ts.x[ts.Len-length-offset : ts.Len-offset]
@@ -142,6 +150,15 @@ func (ts *Series) ToValues(length int, offset int) (x []float64, y []float64) {
 	return
 }
 
+//As ToValues, but returns ErrOutOfRange when length and offset exceed the series.
+func (ts *Series) ToValuesE(length int, offset int) (x []float64, y []float64, err error) {
+	if length < 0 || offset < 0 || length+offset > ts.Len {
+		return nil, nil, ErrOutOfRange
+	}
+	x, y = ts.ToValues(length, offset)
+	return
+}
+
 //Shifts a dataset on the x and y axes
 func (ts *Series) ApplyOffset(x float64, y float64) *Series {
 	newx := make([]float64, ts.Len)
@@ -169,11 +186,26 @@ func (ts *Series) From(time float64) *Series {
 func (ts *Series) Append(toAdd *Series) *Series {
 	newx := make([]float64, 0, ts.Len+toAdd.Len)
 	newy := make([]float64, 0, ts.Len+toAdd.Len)
-	newx = append(ts.x, toAdd.x...)
-	newy = append(ts.y, toAdd.y...)
+	newx = append(append(newx, ts.x...), toAdd.x...)
+	newy = append(append(newy, ts.y...), toAdd.y...)
 	return NewSeriesFrom(newx, newy)
 }
 
+//As Append, but returns ErrNonMonotonicX unless both series are in order and the appended
+//series starts at or after the end of this one.  Use Merge to combine overlapping series.
+func (ts *Series) AppendE(toAdd *Series) (*Series, error) {
+	if toAdd == nil {
+		return nil, ErrInvalidArgument
+	}
+	if !ts.monotonic() || !toAdd.monotonic() {
+		return nil, ErrNonMonotonicX
+	}
+	if ts.Len > 0 && toAdd.Len > 0 && toAdd.x[0] < ts.x[ts.Len-1] {
+		return nil, ErrNonMonotonicX
+	}
+	return ts.Append(toAdd), nil
+}
+
 //Applies two functions.  The map function recieves a series representing a period,
 //and returns a []float64.  The reduce function takes the aggregated results and
 //translates them into a series.
@@ -200,11 +232,33 @@ func (ts *Series) MapReduce(mapFunction func(*Series) (float64, float64), reduce
 	return reduceFunction(mappedx[:p], mappedy[:p])
 }
 
+//As MapReduce, but validates the series and period arguments rather than panicking.
+func (ts *Series) MapReduceE(mapFunction func(*Series) (float64, float64), reduceFunction func([]float64, []float64) *Series, periodLength float64, numberOfPeriods int) (*Series, error) {
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if !(periodLength > 0) || numberOfPeriods < 1 {
+		return nil, ErrInvalidPeriod
+	}
+	if !ts.monotonic() {
+		return nil, ErrNonMonotonicX
+	}
+	return ts.MapReduce(mapFunction, reduceFunction, periodLength, numberOfPeriods), nil
+}
+
 //Slices a series - this is equivalent to go's slice
 func (ts *Series) Slice(start int, end int) *Series {
 	return NewSeriesFrom(ts.x[start:end], ts.y[start:end])
 }
 
+//As Slice, but returns ErrOutOfRange for invalid bounds.
+func (ts *Series) SliceE(start int, end int) (*Series, error) {
+	if start < 0 || end < start || end > ts.Len {
+		return nil, ErrOutOfRange
+	}
+	return ts.Slice(start, end), nil
+}
+
 //Uses binary search to find the earliest ordinal occurance of a x value.
 func (ts *Series) SearchX(value float64) int {
 	xdata := ts.x
@@ -227,6 +281,17 @@ func (ts *Series) SearchX(value float64) int {
 	return i
 }
 
+//As SearchX, but returns an error for empty series or unordered x values.
+func (ts *Series) SearchXE(value float64) (int, error) {
+	if ts.Len == 0 {
+		return 0, ErrEmptySeries
+	}
+	if !ts.monotonic() {
+		return 0, ErrNonMonotonicX
+	}
+	return ts.SearchX(value), nil
+}
+
 func (ts *Series) applyCap() {
 	if ts.seriesCap == 0 {
 		return
@@ -274,3 +339,12 @@ func (ts *Series) SetCap(n int) {
 func (ts *Series) Point(ordinal int) (x float64, y float64) {
 	return ts.x[ordinal], ts.y[ordinal]
 }
+
+//As Point, but returns ErrOutOfRange for an invalid ordinal.
+func (ts *Series) PointE(ordinal int) (x float64, y float64, err error) {
+	if ordinal < 0 || ordinal >= ts.Len {
+		return 0, 0, ErrOutOfRange
+	}
+	x, y = ts.Point(ordinal)
+	return
+}
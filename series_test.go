@@ -1,6 +1,8 @@
 package analytics
 
 import (
+	"errors"
+	"math"
 	"testing"
 )
 
@@ -83,3 +85,104 @@ func StatsCheck(t *testing.T, s *Series, max float64, min float64, mean float64,
 		t.Error("Mean recalculated incorrectly. Was", s.Mean, ", should be", mean)
 	}
 }
+
+func sameValues(got []float64, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !(got[i] == want[i] || math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMovingAverages(t *testing.T) {
+	x, y := testdata[0][0], testdata[0][1]
+	s := NewSeriesFrom(x, y)
+
+	//Each average has an x for every y, where x previously held only the first period values
+	for name, average := range map[string]*Series{"Ma": s.Ma(2), "Ema": s.Ema(2), "Lwma": s.Lwma(2)} {
+		ax, ay := average.ToArrays()
+		if len(ax) != len(x) || len(ay) != len(x) {
+			t.Error(name, "had", len(ax), "x and", len(ay), "y values, should be", len(x))
+			continue
+		}
+		for i := range x {
+			if ax[i] != x[i] {
+				t.Error(name, "x", i, "was", ax[i], ", should be", x[i])
+			}
+		}
+	}
+
+	if _, y := s.Ma(2).Point(4); y != 6 {
+		t.Error("Ma at 4 was", y, ", should be 6")
+	}
+	//Ema writes past the period, which previously indexed outside its buffer
+	if _, y := s.Ema(2).Point(4); y != 8.333333333333334 {
+		t.Error("Ema at 4 was", y, ", should be", 8.333333333333334)
+	}
+}
+
+func TestTrendChanges(t *testing.T) {
+	s := NewSeriesFrom([]float64{1, 2, 3, 4, 5}, []float64{1, 3, 2, 4, 1})
+	changes, err := s.TrendChangesE()
+	if err != nil {
+		t.Fatal("TrendChangesE returned", err)
+	}
+	if changes.Len != 3 || !sameValues(changes.x, []float64{2, 3, 4}) || !sameValues(changes.y, []float64{3, 2, 4}) {
+		t.Error("Trend changes were", changes.x, changes.y)
+	}
+}
+
+func TestSeriesErrorVariants(t *testing.T) {
+	x, y := testdata[0][0], testdata[0][1]
+	s := NewSeriesFrom(x, y)
+
+	if _, err := s.LastE(len(x) + 1); !errors.Is(err, ErrOutOfRange) {
+		t.Error("LastE returned", err, ", should be", ErrOutOfRange)
+	}
+	if _, err := s.SliceE(3, 2); !errors.Is(err, ErrOutOfRange) {
+		t.Error("SliceE returned", err, ", should be", ErrOutOfRange)
+	}
+	if _, _, err := s.PointE(len(x)); !errors.Is(err, ErrOutOfRange) {
+		t.Error("PointE returned", err, ", should be", ErrOutOfRange)
+	}
+	if _, err := NewSeries().SearchXE(1); !errors.Is(err, ErrEmptySeries) {
+		t.Error("SearchXE returned", err, ", should be", ErrEmptySeries)
+	}
+	if _, err := s.MaE(len(x) + 1); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("MaE returned", err, ", should be", ErrInsufficientPoints)
+	}
+	if _, err := s.EmaE(0); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("EmaE returned", err, ", should be", ErrInvalidPeriod)
+	}
+
+	ema, err := s.EmaE(2)
+	if err != nil {
+		t.Fatal("EmaE returned", err)
+	}
+	StatsCheck(t, ema, 8.333333333333334, 1, 4.6, len(x))
+
+	unordered := NewSeriesFrom([]float64{1, 3, 2}, []float64{1, 2, 3})
+	if _, err := unordered.SearchXE(2); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("SearchXE returned", err, ", should be", ErrNonMonotonicX)
+	}
+}
+
+func TestAppendE(t *testing.T) {
+	s := NewSeriesFrom([]float64{1, 2, 3, 4}, []float64{1, 2, 3, 4})
+	head := s.Slice(0, 2)
+	appended, err := head.AppendE(NewSeriesFrom([]float64{5}, []float64{50}))
+	if err != nil || appended.Len != 3 || appended.y[2] != 50 {
+		t.Error("AppendE returned", appended, err)
+	}
+	//Appending to a slice must not overwrite the series it was sliced from
+	if s.y[2] != 3 {
+		t.Error("Append overwrote the original series")
+	}
+	if _, err := s.AppendE(NewSeriesFrom([]float64{3.5}, []float64{0})); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("Overlapping series returned", err)
+	}
+}
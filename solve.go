@@ -0,0 +1,246 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+//Number of intervals sampled by Solve to bracket roots of models without a closed form inverse
+const solveSamples = 1000
+
+//Returns every x in [lo, hi] at which the model equals y, in ascending order, e.g. when
+//a trend will reach a threshold.  Linear, logarithmic, power and exponential models are
+//inverted exactly and polynomial roots are bracketed between stationary points.  Other
+//models are sampled at 1000 intervals and refined by Brent's method, so roots where the
+//curve touches y without crossing it, or closer together than the sampling, may be missed.
+//A model that is constant at y over the range returns lo.
+func Solve(m Model, y float64, lo float64, hi float64) ([]float64, error) {
+	m = unwrapModel(m)
+	if m == nil {
+		return nil, ErrNoFit
+	}
+	if !finite(y, lo, hi) || lo > hi {
+		return nil, ErrInvalidArgument
+	}
+
+	var candidates []float64
+	switch model := m.(type) {
+	case *LinearModel:
+		candidates = solveLinearEquation(model.Gradient, model.Intercept+model.YOffset-y, model.XOffset, lo, model.Eval(lo) == y)
+	case *LinearThroughOriginModel:
+		candidates = solveLinearEquation(model.Gradient, -y, 0, lo, model.Eval(lo) == y)
+	case *LogarithmicModel:
+		if model.B == 0 {
+			candidates = solveLinearEquation(0, 0, 0, lo, model.Eval(lo) == y)
+		} else {
+			candidates = []float64{model.XOffset + math.Exp((y-model.A-model.YOffset)/model.B)}
+		}
+	case *PowerModel:
+		if ratio := (y - model.YOffset) / model.A; ratio > 0 && model.B != 0 {
+			candidates = []float64{model.XOffset + math.Pow(ratio, 1/model.B)}
+		}
+	case *ExponentialModel:
+		if ratio := (y - model.YOffset) / model.A; ratio > 0 && model.B != 0 {
+			candidates = []float64{model.XOffset + math.Log(ratio)/model.B}
+		} else if model.Eval(lo) == y {
+			candidates = []float64{lo}
+		}
+	case *PolynomialModel:
+		c, center, scale := model.polynomial()
+		c[0] -= y
+		for _, t := range polynomialRoots(c, (lo-center)/scale, (hi-center)/scale) {
+			candidates = append(candidates, center+t*scale)
+		}
+	default:
+		candidates = bracketRoots(func(x float64) float64 { return m.Eval(x) - y }, lo, hi, solveSamples)
+	}
+
+	roots := []float64{}
+	for _, x := range candidates {
+		if x >= lo && x <= hi {
+			roots = append(roots, x)
+		}
+	}
+	sort.Float64s(roots)
+	return roots, nil
+}
+
+//Root of gradient * (x - xoffset) + constant.  A flat line has no root, or lo if it lies on y.
+func solveLinearEquation(gradient float64, constant float64, xoffset float64, lo float64, flatOnY bool) []float64 {
+	if gradient == 0 {
+		if flatOnY {
+			return []float64{lo}
+		}
+		return nil
+	}
+	return []float64{xoffset - constant/gradient}
+}
+
+//Returns the model's coefficients, including YOffset, in the variable t = (x - center) / scale
+func (m *PolynomialModel) polynomial() (c []float64, center float64, scale float64) {
+	c, center, scale = m.form()
+	c = append([]float64{}, c...)
+	if len(c) == 0 {
+		c = []float64{0}
+	}
+	c[0] += m.YOffset
+	return
+}
+
+//Returns the x in [lo, hi] at which the derivative of the polynomial is zero, in ascending order
+func (m *PolynomialModel) StationaryPoints(lo float64, hi float64) []float64 {
+	c, center, scale := m.polynomial()
+	var points []float64
+	for _, t := range polynomialRoots(polynomialDerivative(c), (lo-center)/scale, (hi-center)/scale) {
+		points = append(points, center+t*scale)
+	}
+	return points
+}
+
+//Returns the x and y of the smallest value of the polynomial over [lo, hi]
+func (m *PolynomialModel) Minimum(lo float64, hi float64) (x float64, y float64) {
+	return m.extremum(lo, hi, -1)
+}
+
+//Returns the x and y of the largest value of the polynomial over [lo, hi]
+func (m *PolynomialModel) Maximum(lo float64, hi float64) (x float64, y float64) {
+	return m.extremum(lo, hi, 1)
+}
+
+//The extremum is at an end of the range or a stationary point
+func (m *PolynomialModel) extremum(lo float64, hi float64, sign float64) (x float64, y float64) {
+	x, y = lo, m.Eval(lo)
+	for _, candidate := range append(m.StationaryPoints(lo, hi), hi) {
+		if value := m.Eval(candidate); sign*value > sign*y {
+			x, y = candidate, value
+		}
+	}
+	return
+}
+
+func polynomialDerivative(c []float64) []float64 {
+	if len(c) <= 1 {
+		return []float64{0}
+	}
+	d := make([]float64, len(c)-1)
+	for j := range d {
+		d[j] = float64(j+1) * c[j+1]
+	}
+	return d
+}
+
+func evalPolynomial(c []float64, t float64) float64 {
+	var y float64
+	for j := len(c) - 1; j >= 0; j-- {
+		y = y*t + c[j]
+	}
+	return y
+}
+
+//Real roots of Σ c[j] t^j in [lo, hi].  The polynomial is monotonic between the
+//roots of its derivative, so each of those intervals holds at most one root.
+func polynomialRoots(c []float64, lo float64, hi float64) []float64 {
+	degree := len(c) - 1
+	for degree > 0 && c[degree] == 0 {
+		degree--
+	}
+	c = c[:degree+1]
+	switch degree {
+	case 0:
+		return nil
+	case 1:
+		if t := -c[0] / c[1]; t >= lo && t <= hi {
+			return []float64{t}
+		}
+		return nil
+	}
+
+	f := func(t float64) float64 { return evalPolynomial(c, t) }
+	bounds := append(append([]float64{lo}, polynomialRoots(polynomialDerivative(c), lo, hi)...), hi)
+	var roots []float64
+	for i := 0; i+1 < len(bounds); i++ {
+		a, b := bounds[i], bounds[i+1]
+		fa, fb := f(a), f(b)
+		switch {
+		case fa == 0:
+			if len(roots) == 0 || roots[len(roots)-1] != a {
+				roots = append(roots, a)
+			}
+		case fa*fb < 0:
+			roots = append(roots, brent(f, a, b))
+		}
+	}
+	if f(hi) == 0 && (len(roots) == 0 || roots[len(roots)-1] != hi) {
+		roots = append(roots, hi)
+	}
+	return roots
+}
+
+//Finds roots by sampling f at intervals over [lo, hi] and refining each sign change
+func bracketRoots(f func(float64) float64, lo float64, hi float64, samples int) []float64 {
+	var roots []float64
+	a, fa := lo, f(lo)
+	if fa == 0 {
+		roots = append(roots, lo)
+	}
+	for i := 1; i <= samples; i++ {
+		b := lo + (hi-lo)*float64(i)/float64(samples)
+		fb := f(b)
+		if fb == 0 {
+			roots = append(roots, b)
+		} else if fa*fb < 0 {
+			roots = append(roots, brent(f, a, b))
+		}
+		a, fa = b, fb
+		if hi == lo {
+			break
+		}
+	}
+	return roots
+}
+
+//Brent's method for the root of f in [a, b], where f(a) and f(b) differ in sign.
+//Combines inverse quadratic interpolation and the secant method with bisection.
+func brent(f func(float64) float64, a float64, b float64) float64 {
+	fa, fb := f(a), f(b)
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	c, fc, d := a, fa, a
+	bisected := true
+	for i := 0; i < 200 && fb != 0; i++ {
+		tolerance := 2.2e-16*math.Abs(b) + 1e-300
+		if math.Abs(b-a) <= tolerance {
+			break
+		}
+		var s float64
+		if fa != fc && fb != fc {
+			s = a*fb*fc/((fa-fb)*(fa-fc)) + b*fa*fc/((fb-fa)*(fb-fc)) + c*fa*fb/((fc-fa)*(fc-fb))
+		} else {
+			s = b - fb*(b-a)/(fb-fa)
+		}
+		//Fall back to bisection when the interpolated step is poor
+		mid := (3*a + b) / 4
+		if (s-mid)*(s-b) >= 0 ||
+			(bisected && math.Abs(s-b) >= math.Abs(b-c)/2) ||
+			(!bisected && math.Abs(s-b) >= math.Abs(c-d)/2) ||
+			(bisected && math.Abs(b-c) < tolerance) ||
+			(!bisected && math.Abs(c-d) < tolerance) {
+			s = (a + b) / 2
+			bisected = true
+		} else {
+			bisected = false
+		}
+		fs := f(s)
+		d, c, fc = c, b, fb
+		if fa*fs < 0 {
+			b, fb = s, fs
+		} else {
+			a, fa = s, fs
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+	}
+	return b
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	//Disk usage growing exponentially reaches 90% of capacity
+	exponential := &ExponentialModel{A: 10, B: 0.05, XOffset: 100, YOffset: 5}
+	roots, err := Solve(FitParameters{Model: exponential}, 90, 0, 1000)
+	if err != nil || len(roots) != 1 || math.Abs(exponential.Eval(roots[0])-90) > 1e-9 {
+		t.Error("Exponential roots were", roots, err)
+	}
+	if roots, _ := Solve(exponential, 90, 0, 100); len(roots) != 0 {
+		t.Error("Roots outside the range were returned:", roots)
+	}
+
+	//(x - 1)(x - 2)(x - 3) has three roots and two stationary points
+	cubic := &PolynomialModel{Coeffs: []float64{-6, 11, -6, 1}}
+	roots, _ = Solve(cubic, 0, -10, 10)
+	if len(roots) != 3 {
+		t.Fatal("Cubic roots were", roots)
+	}
+	for i, want := range []float64{1, 2, 3} {
+		if math.Abs(roots[i]-want) > 1e-12 {
+			t.Error("Root", i, "was", roots[i], ", should be", want)
+		}
+	}
+	points := cubic.StationaryPoints(-10, 10)
+	if len(points) != 2 || math.Abs(points[0]-(2-1/math.Sqrt(3))) > 1e-12 {
+		t.Error("Stationary points were", points)
+	}
+	if x, y := cubic.Maximum(0, 2.5); math.Abs(x-(2-1/math.Sqrt(3))) > 1e-12 || math.Abs(y-cubic.Eval(x)) > 0 {
+		t.Error("Maximum was", x, y)
+	}
+	if x, _ := cubic.Minimum(0, 5); x != 0 {
+		t.Error("Minimum was at", x)
+	}
+
+	//Models without an inverse are bracketed and refined
+	sine := NewSeriesFrom([]float64{0, 1, 2, 3}, []float64{0, 1, 0, -1})
+	spline, _ := sine.FitCubicSplineE(SplineBoundary{})
+	roots, _ = Solve(spline, 0.5, 0, 3)
+	if len(roots) != 2 {
+		t.Error("Spline roots were", roots)
+	}
+	for _, x := range roots {
+		if math.Abs(spline.Eval(x)-0.5) > 1e-12 {
+			t.Error("Spline at", x, "was", spline.Eval(x))
+		}
+	}
+
+	if _, err := Solve(exponential, 90, 10, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Solve with lo > hi returned", err)
+	}
+	if _, err := Solve(FitParameters{}, 90, 0, 1); !errors.Is(err, ErrNoFit) {
+		t.Error("Solve without a model returned", err)
+	}
+}
@@ -0,0 +1,365 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+//Boundary conditions for cubic splines
+const (
+	SplineNatural  = iota //Zero second derivative at both ends
+	SplineClamped         //First derivatives at both ends given by StartSlope and EndSlope
+	SplineNotAKnot        //Third derivative continuous at the second and penultimate knots
+)
+
+//The boundary condition used when fitting a cubic spline
+type SplineBoundary struct {
+	Condition  int //One of the Spline constants
+	StartSlope float64
+	EndSlope   float64
+}
+
+//A piecewise cubic through a set of knots.  Between Knots[i] and Knots[i+1]
+//the curve is a + b*t + c*t^2 + d*t^3 where t = x - Knots[i].
+//Outside the knots the end pieces are extended.
+type SplineModel struct {
+	knots []float64
+	a     []float64
+	b     []float64
+	c     []float64
+	d     []float64
+	name  string
+}
+
+//Fits a cubic spline passing through every point of the series.
+func (ts *Series) FitCubicSpline(boundary SplineBoundary) FitParameters {
+	return mustFit(ts.FitCubicSplineE(boundary))
+}
+
+//As FitCubicSpline, but returns an error for invalid input.
+//Natural and clamped splines need 2 points, not-a-knot splines need 4.
+func (ts *Series) FitCubicSplineE(boundary SplineBoundary) (params FitParameters, err error) {
+	min := 2
+	switch boundary.Condition {
+	case SplineNatural:
+	case SplineClamped:
+		if !finite(boundary.StartSlope, boundary.EndSlope) {
+			return params, ErrInvalidArgument
+		}
+	case SplineNotAKnot:
+		min = 4
+	default:
+		return params, ErrInvalidArgument
+	}
+	if err = ts.checkInterpolation(min); err != nil {
+		return
+	}
+
+	m, err := splineSecondDerivatives(ts.x, ts.y, boundary)
+	if err != nil {
+		return
+	}
+	n := ts.Len
+	spline := newSplineModel(ts.x, "cubic spline")
+	for i := 0; i < n-1; i++ {
+		h := ts.x[i+1] - ts.x[i]
+		spline.a[i] = ts.y[i]
+		spline.b[i] = (ts.y[i+1]-ts.y[i])/h - h*(2*m[i]+m[i+1])/6
+		spline.c[i] = m[i] / 2
+		spline.d[i] = (m[i+1] - m[i]) / (6 * h)
+	}
+
+	params = FitParameters{
+		Type:  FitTypeCubicSpline,
+		Model: spline,
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Solves for the second derivative of the spline at each knot
+func splineSecondDerivatives(x []float64, y []float64, boundary SplineBoundary) ([]float64, error) {
+	n := len(x)
+	h := make([]float64, n-1)
+	delta := make([]float64, n-1)
+	for i := range h {
+		h[i] = x[i+1] - x[i]
+		delta[i] = (y[i+1] - y[i]) / h[i]
+	}
+
+	sub := make([]float64, n)
+	diag := make([]float64, n)
+	sup := make([]float64, n)
+	rhs := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		sub[i] = h[i-1]
+		diag[i] = 2 * (h[i-1] + h[i])
+		sup[i] = h[i]
+		rhs[i] = 6 * (delta[i] - delta[i-1])
+	}
+
+	switch boundary.Condition {
+	case SplineNatural:
+		diag[0] = 1
+		diag[n-1] = 1
+	case SplineClamped:
+		diag[0] = 2 * h[0]
+		sup[0] = h[0]
+		rhs[0] = 6 * (delta[0] - boundary.StartSlope)
+		sub[n-1] = h[n-2]
+		diag[n-1] = 2 * h[n-2]
+		rhs[n-1] = 6 * (boundary.EndSlope - delta[n-2])
+	case SplineNotAKnot:
+		//Substitute the end second derivatives, which are linear in their
+		//neighbours, into the first and last interior equations.
+		h0, h1 := h[0], h[1]
+		diag[1] = (h0 + h1) * (h0 + 2*h1) / h1
+		sup[1] = (h1*h1 - h0*h0) / h1
+		hp, hl := h[n-3], h[n-2]
+		sub[n-2] = (hp*hp - hl*hl) / hp
+		diag[n-2] = (hp + hl) * (hp + 2*hl) / hp
+
+		inner, err := solveTridiagonal(sub[1:n-1], diag[1:n-1], sup[1:n-1], rhs[1:n-1])
+		if err != nil {
+			return nil, err
+		}
+		m := make([]float64, n)
+		copy(m[1:], inner)
+		m[0] = ((h0+h1)*m[1] - h0*m[2]) / h1
+		m[n-1] = ((hp+hl)*m[n-2] - hl*m[n-3]) / hp
+		return m, nil
+	}
+	return solveTridiagonal(sub, diag, sup, rhs)
+}
+
+//Fits a monotone piecewise cubic Hermite interpolant (Fritsch–Carlson PCHIP).
+//The curve passes through every point and introduces no extrema between them.
+func (ts *Series) FitPCHIP() FitParameters {
+	return mustFit(ts.FitPCHIPE())
+}
+
+//As FitPCHIP, but returns an error for invalid input.
+func (ts *Series) FitPCHIPE() (params FitParameters, err error) {
+	if err = ts.checkInterpolation(2); err != nil {
+		return
+	}
+	n := ts.Len
+	h, delta := ts.secants()
+	slopes := make([]float64, n)
+	if n == 2 {
+		slopes[0], slopes[1] = delta[0], delta[0]
+	} else {
+		for k := 1; k < n-1; k++ {
+			if delta[k-1]*delta[k] <= 0 {
+				continue
+			}
+			w1 := 2*h[k] + h[k-1]
+			w2 := h[k] + 2*h[k-1]
+			slopes[k] = (w1 + w2) / (w1/delta[k-1] + w2/delta[k])
+		}
+		slopes[0] = pchipEndSlope(h[0], h[1], delta[0], delta[1])
+		slopes[n-1] = pchipEndSlope(h[n-2], h[n-3], delta[n-2], delta[n-3])
+	}
+
+	params = FitParameters{
+		Type:  FitTypePCHIP,
+		Model: newHermiteSpline(ts.x, ts.y, slopes, "pchip"),
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Shape preserving three point estimate of the slope at an end knot
+func pchipEndSlope(h0 float64, h1 float64, delta0 float64, delta1 float64) float64 {
+	d := ((2*h0+h1)*delta0 - h0*delta1) / (h0 + h1)
+	if math.Signbit(d) != math.Signbit(delta0) || delta0 == 0 {
+		return 0
+	}
+	if math.Signbit(delta0) != math.Signbit(delta1) && math.Abs(d) > math.Abs(3*delta0) {
+		return 3 * delta0
+	}
+	return d
+}
+
+//Fits an Akima spline, which follows the data without the overshoot of a cubic
+//spline near outliers and abrupt changes in gradient.
+func (ts *Series) FitAkima() FitParameters {
+	return mustFit(ts.FitAkimaE())
+}
+
+//As FitAkima, but returns an error for invalid input.  At least 3 points are needed.
+func (ts *Series) FitAkimaE() (params FitParameters, err error) {
+	if err = ts.checkInterpolation(3); err != nil {
+		return
+	}
+	n := ts.Len
+	_, delta := ts.secants()
+
+	//Secant slopes, extended by two on each side
+	m := make([]float64, n+3)
+	copy(m[2:], delta)
+	m[1] = 2*m[2] - m[3]
+	m[0] = 2*m[1] - m[2]
+	m[n+1] = 2*m[n] - m[n-1]
+	m[n+2] = 2*m[n+1] - m[n]
+
+	slopes := make([]float64, n)
+	for i := range slopes {
+		w1 := math.Abs(m[i+3] - m[i+2])
+		w2 := math.Abs(m[i+1] - m[i])
+		if w1+w2 == 0 {
+			slopes[i] = (m[i+1] + m[i+2]) / 2
+		} else {
+			slopes[i] = (w1*m[i+1] + w2*m[i+2]) / (w1 + w2)
+		}
+	}
+
+	params = FitParameters{
+		Type:  FitTypeAkima,
+		Model: newHermiteSpline(ts.x, ts.y, slopes, "akima"),
+	}
+	params.Report = ts.GoodnessOfFit(params.Model)
+	return
+}
+
+//Validates a series for interpolation, which needs strictly increasing x
+func (ts *Series) checkInterpolation(min int) error {
+	if err := ts.checkFit(min); err != nil {
+		return err
+	}
+	if !ts.strictlyIncreasing() {
+		return ErrNonMonotonicX
+	}
+	return nil
+}
+
+//Returns the width and gradient of each interval
+func (ts *Series) secants() (h []float64, delta []float64) {
+	h = make([]float64, ts.Len-1)
+	delta = make([]float64, ts.Len-1)
+	for i := range h {
+		h[i] = ts.x[i+1] - ts.x[i]
+		delta[i] = (ts.y[i+1] - ts.y[i]) / h[i]
+	}
+	return
+}
+
+//Builds a piecewise cubic from the values and first derivatives at each knot
+func newHermiteSpline(x []float64, y []float64, slopes []float64, name string) *SplineModel {
+	spline := newSplineModel(x, name)
+	for i := range spline.a {
+		h := x[i+1] - x[i]
+		delta := (y[i+1] - y[i]) / h
+		spline.a[i] = y[i]
+		spline.b[i] = slopes[i]
+		spline.c[i] = (3*delta - 2*slopes[i] - slopes[i+1]) / h
+		spline.d[i] = (slopes[i] + slopes[i+1] - 2*delta) / (h * h)
+	}
+	return spline
+}
+
+func newSplineModel(knots []float64, name string) *SplineModel {
+	n := len(knots) - 1
+	k := make([]float64, len(knots))
+	copy(k, knots)
+	return &SplineModel{
+		knots: k,
+		a:     make([]float64, n),
+		b:     make([]float64, n),
+		c:     make([]float64, n),
+		d:     make([]float64, n),
+		name:  name,
+	}
+}
+
+//Finds the piece used to evaluate x
+func (m *SplineModel) piece(x float64) int {
+	i := sort.SearchFloat64s(m.knots, x) - 1
+	if i < 0 {
+		return 0
+	}
+	if i > len(m.a)-1 {
+		return len(m.a) - 1
+	}
+	return i
+}
+
+func (m *SplineModel) Eval(x float64) float64 {
+	i := m.piece(x)
+	t := x - m.knots[i]
+	return m.a[i] + t*(m.b[i]+t*(m.c[i]+t*m.d[i]))
+}
+
+//Evaluates the nth derivative of the spline at x
+func (m *SplineModel) Derivative(x float64, order int) float64 {
+	i := m.piece(x)
+	t := x - m.knots[i]
+	switch order {
+	case 0:
+		return m.Eval(x)
+	case 1:
+		return m.b[i] + t*(2*m.c[i]+t*3*m.d[i])
+	case 2:
+		return 2*m.c[i] + 6*m.d[i]*t
+	case 3:
+		return 6 * m.d[i]
+	}
+	return 0
+}
+
+//Integrates the spline from a to b
+func (m *SplineModel) Integral(a float64, b float64) float64 {
+	if a > b {
+		return -m.Integral(b, a)
+	}
+	var area float64
+	for i := m.piece(a); i < len(m.a); i++ {
+		start := a
+		if i > 0 && m.knots[i] > start {
+			start = m.knots[i]
+		}
+		end := b
+		if i < len(m.a)-1 && m.knots[i+1] < end {
+			end = m.knots[i+1]
+		}
+		area += m.antiderivative(i, end) - m.antiderivative(i, start)
+		if end == b {
+			break
+		}
+	}
+	return area
+}
+
+//Integral of piece i from its knot to x
+func (m *SplineModel) antiderivative(i int, x float64) float64 {
+	t := x - m.knots[i]
+	return t * (m.a[i] + t*(m.b[i]/2+t*(m.c[i]/3+t*m.d[i]/4)))
+}
+
+//Returns the knot positions
+func (m *SplineModel) Knots() []float64 {
+	return append([]float64{}, m.knots...)
+}
+
+//Returns a, b, c, d for each piece in turn
+func (m *SplineModel) Coefficients() []float64 {
+	coeffs := make([]float64, 0, 4*len(m.a))
+	for i := range m.a {
+		coeffs = append(coeffs, m.a[i], m.b[i], m.c[i], m.d[i])
+	}
+	return coeffs
+}
+
+//An interpolating spline is determined by its knots, one value per point
+func (m *SplineModel) Parameters() float64 {
+	return float64(len(m.knots))
+}
+
+func (m *SplineModel) Name() string {
+	return m.name
+}
+
+func (m *SplineModel) String() string {
+	return fmt.Sprintf("%s with %d knots from %g to %g", m.name, len(m.knots), m.knots[0], m.knots[len(m.knots)-1])
+}
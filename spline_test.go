@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitCubicSpline(t *testing.T) {
+	x := []float64{0, 1, 2, 3, 4, 5}
+	y := make([]float64, len(x))
+	for i := range x {
+		y[i] = x[i] * x[i] * x[i]
+	}
+	s := NewSeriesFrom(x, y)
+
+	//A cubic is reproduced exactly by not-a-knot and correctly clamped splines
+	boundaries := []SplineBoundary{
+		{Condition: SplineNotAKnot},
+		{Condition: SplineClamped, StartSlope: 0, EndSlope: 75},
+	}
+	for _, boundary := range boundaries {
+		fit := s.FitCubicSpline(boundary)
+		spline := fit.Model.(*SplineModel)
+		if v := Extrapolate(fit, 2.5); math.Abs(v-15.625) > 1e-9 {
+			t.Error("Condition", boundary.Condition, "evaluated to", v, ", should be 15.625")
+		}
+		if v := spline.Derivative(2.5, 1); math.Abs(v-18.75) > 1e-9 {
+			t.Error("Condition", boundary.Condition, "derivative was", v, ", should be 18.75")
+		}
+		if v := spline.Integral(0.5, 4.5); math.Abs(v-(math.Pow(4.5, 4)-math.Pow(0.5, 4))/4) > 1e-9 {
+			t.Error("Condition", boundary.Condition, "integral was", v)
+		}
+	}
+
+	natural := s.FitCubicSpline(SplineBoundary{Condition: SplineNatural}).Model.(*SplineModel)
+	for _, knot := range []float64{0, 5} {
+		if v := natural.Derivative(knot, 2); math.Abs(v) > 1e-9 {
+			t.Error("Natural spline second derivative at", knot, "was", v, ", should be 0")
+		}
+	}
+	for i := range x {
+		if v := natural.Eval(x[i]); math.Abs(v-y[i]) > 1e-9 {
+			t.Error("Natural spline did not pass through", x[i], y[i])
+		}
+	}
+
+	if _, err := s.Slice(0, 3).FitCubicSplineE(SplineBoundary{Condition: SplineNotAKnot}); !errors.Is(err, ErrInsufficientPoints) {
+		t.Error("FitCubicSplineE returned", err, ", should be", ErrInsufficientPoints)
+	}
+}
+
+func TestShapePreservingInterpolation(t *testing.T) {
+	x := []float64{0, 1, 2, 3, 4, 5, 6}
+	y := []float64{0, 0, 0, 1, 1, 1, 1}
+	s := NewSeriesFrom(x, y)
+
+	for _, fit := range []FitParameters{s.FitPCHIP(), s.FitAkima()} {
+		for i := 0; i < len(x)-1; i++ {
+			for xi := x[i]; xi <= x[i+1]; xi += 0.05 {
+				v := fit.Eval(xi)
+				if v < math.Min(y[i], y[i+1])-1e-12 || v > math.Max(y[i], y[i+1])+1e-12 {
+					t.Fatal(fit.Name(), "overshot at", xi, "with", v)
+				}
+			}
+		}
+		for i := range x {
+			if v := fit.Eval(x[i]); math.Abs(v-y[i]) > 1e-12 {
+				t.Error(fit.Name(), "did not pass through", x[i], y[i])
+			}
+		}
+	}
+}
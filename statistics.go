@@ -59,6 +59,14 @@ func (ts *Series) ITrend(alpha float64) (itrendSeries *Series) {
 	return t
 }
 
+//As ITrend, but returns ErrInsufficientPoints for series shorter than 3 points.
+func (ts *Series) ITrendE(alpha float64) (*Series, error) {
+	if ts.Len < 3 {
+		return nil, ErrInsufficientPoints
+	}
+	return ts.ITrend(alpha), nil
+}
+
 // Standard deviation
 func (ts *Series) StDev() float64 {
 	if ts.Len == 0 {
@@ -87,7 +95,7 @@ func (ts *Series) MeanDev() float64 {
 func (ts *Series) Ma(period int) *Series {
 	var l int = ts.Len
 	var sum float64 = 0
-	var bufferx = make([]float64, period, ts.Len)
+	var bufferx = make([]float64, ts.Len)
 
 	var buffery = make([]float64, period, ts.Len)
 	copy(bufferx, ts.x)
@@ -102,14 +110,22 @@ func (ts *Series) Ma(period int) *Series {
 	return NewSeriesFrom(bufferx, buffery)
 }
 
+//As Ma, but validates the period against the length of the series.
+func (ts *Series) MaE(period int) (*Series, error) {
+	if err := ts.checkPeriod(period); err != nil {
+		return nil, err
+	}
+	return ts.Ma(period), nil
+}
+
 //Exponential moving average
 func (ts *Series) Ema(period int) *Series {
 
 	var l int = ts.Len
 
-	var bufferx = make([]float64, period, ts.Len)
+	var bufferx = make([]float64, ts.Len)
 	copy(bufferx, ts.x)
-	var buffery = make([]float64, period, ts.Len)
+	var buffery = make([]float64, ts.Len)
 	copy(buffery, ts.y[:period])
 	var m float64 = 2 / (float64(period) + 1) // Multiplier
 
@@ -119,13 +135,21 @@ func (ts *Series) Ema(period int) *Series {
 	return NewSeriesFrom(bufferx, buffery)
 }
 
+//As Ema, but validates the period against the length of the series.
+func (ts *Series) EmaE(period int) (*Series, error) {
+	if err := ts.checkPeriod(period); err != nil {
+		return nil, err
+	}
+	return ts.Ema(period), nil
+}
+
 //Linear weighted moving average
 func (ts *Series) Lwma(period int) *Series {
 
 	var l int = ts.Len
 	var sum float64 = 0
 
-	var bufferx = make([]float64, period, ts.Len)
+	var bufferx = make([]float64, ts.Len)
 	copy(bufferx, ts.x)
 	var buffery = make([]float64, period, ts.Len)
 	copy(buffery, ts.y[:period])
@@ -141,6 +165,27 @@ func (ts *Series) Lwma(period int) *Series {
 	return NewSeriesFrom(bufferx, buffery)
 }
 
+//As Lwma, but validates the period against the length of the series.
+func (ts *Series) LwmaE(period int) (*Series, error) {
+	if err := ts.checkPeriod(period); err != nil {
+		return nil, err
+	}
+	return ts.Lwma(period), nil
+}
+
+func (ts *Series) checkPeriod(period int) error {
+	if period < 1 {
+		return ErrInvalidPeriod
+	}
+	if ts.Len == 0 {
+		return ErrEmptySeries
+	}
+	if period > ts.Len {
+		return ErrInsufficientPoints
+	}
+	return nil
+}
+
 //Recent trends
 func (ts *Series) RecentTrends(n int) []*Series {
 	ret := []*Series{}
@@ -177,8 +222,8 @@ func (ts *Series) RecentTrends(n int) []*Series {
 
 //Peak and trough data points
 func (ts *Series) TrendChanges() *Series {
-	bufferx := make([]float64, 500)
-	buffery := make([]float64, 500)
+	bufferx := make([]float64, 0, ts.Len)
+	buffery := make([]float64, 0, ts.Len)
 	l := ts.Len
 	dirup := ts.y[1] > ts.y[0]
 	for i := 1; i < l; i++ {
@@ -191,3 +236,11 @@ func (ts *Series) TrendChanges() *Series {
 	}
 	return NewSeriesFrom(bufferx, buffery)
 }
+
+//As TrendChanges, but returns ErrInsufficientPoints for series shorter than 2 points.
+func (ts *Series) TrendChangesE() (*Series, error) {
+	if ts.Len < 2 {
+		return nil, ErrInsufficientPoints
+	}
+	return ts.TrendChanges(), nil
+}
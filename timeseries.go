@@ -0,0 +1,217 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+//A series indexed by time.  Timestamps are kept exactly, alongside a Series whose x values
+//are seconds since the Unix epoch for use with the fitting and statistics functions.
+type TimeSeries struct {
+	Max    float64
+	Min    float64
+	Mean   float64
+	Len    int
+	times  []time.Time
+	series *Series
+}
+
+//Converts a time to an x value in seconds since the Unix epoch, as used by TimeSeries.Series.
+//The conversion keeps about microsecond precision for present day times.
+func TimeToX(t time.Time) float64 {
+	return float64(t.Unix()) + float64(t.Nanosecond())/1e9
+}
+
+//Converts an x value in seconds since the Unix epoch to a time, e.g. to read the result of Solve
+func XToTime(x float64) time.Time {
+	seconds := int64(x)
+	if float64(seconds) > x {
+		seconds--
+	}
+	return time.Unix(seconds, int64((x-float64(seconds))*1e9+0.5))
+}
+
+//Creates an empty time series
+func NewTimeSeries() *TimeSeries {
+	return &TimeSeries{series: NewSeries()}
+}
+
+//Creates a time series from times and values, which must be the same length.
+//Returns ErrNonMonotonicX if the times are not in ascending order.
+func NewTimeSeriesFrom(times []time.Time, y []float64) (*TimeSeries, error) {
+	if len(times) != len(y) {
+		return nil, ErrInvalidArgument
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i].Before(times[i-1]) {
+			return nil, ErrNonMonotonicX
+		}
+	}
+	ts := &TimeSeries{
+		times:  append([]time.Time{}, times...),
+		series: NewSeriesFrom(make([]float64, len(times)), append([]float64{}, y...)),
+	}
+	for i, t := range times {
+		ts.series.x[i] = TimeToX(t)
+	}
+	ts.updateStats()
+	return ts, nil
+}
+
+//Creates a time series from a series whose x values are seconds since the Unix epoch.
+//Returns ErrNonMonotonicX if the x values are not in ascending order.
+func NewTimeSeriesFromSeries(s *Series) (*TimeSeries, error) {
+	ts := NewTimeSeries()
+	for i := range s.x {
+		if err := ts.AddE(XToTime(s.x[i]), s.y[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+func (ts *TimeSeries) updateStats() {
+	ts.Max, ts.Min, ts.Mean, ts.Len = ts.series.Max, ts.series.Min, ts.series.Mean, ts.series.Len
+}
+
+//Add a new value to the end of the series.  Panics if t is before the last time.
+func (ts *TimeSeries) Add(t time.Time, y float64) {
+	if err := ts.AddE(t, y); err != nil {
+		panic(err)
+	}
+}
+
+//As Add, but returns ErrNonMonotonicX if t is before the last time.
+func (ts *TimeSeries) AddE(t time.Time, y float64) error {
+	if ts.Len > 0 && t.Before(ts.times[ts.Len-1]) {
+		return ErrNonMonotonicX
+	}
+	ts.times = append(ts.times, t)
+	ts.series.Add(TimeToX(t), y)
+	ts.updateStats()
+	return nil
+}
+
+//Returns the exact time and the value at the ordinal position
+func (ts *TimeSeries) Point(ordinal int) (time.Time, float64) {
+	return ts.times[ordinal], ts.series.y[ordinal]
+}
+
+//Returns a copy of the times
+func (ts *TimeSeries) Times() []time.Time {
+	return append([]time.Time{}, ts.times...)
+}
+
+//Returns a copy of the values
+func (ts *TimeSeries) Values() []float64 {
+	return append([]float64{}, ts.series.y...)
+}
+
+//Returns a copy of the data as a Series with x in seconds since the Unix epoch, for fitting.
+//Use TimeToX to evaluate the fitted models at a time.
+func (ts *TimeSeries) Series() *Series {
+	return NewSeriesFrom(append([]float64{}, ts.series.x...), append([]float64{}, ts.series.y...))
+}
+
+//Returns the ordinal of the first point at or after t, or Len if there is none
+func (ts *TimeSeries) SearchTime(t time.Time) int {
+	return sort.Search(len(ts.times), func(i int) bool {
+		return !ts.times[i].Before(t)
+	})
+}
+
+//Creates a new time series of the points at or after t
+func (ts *TimeSeries) From(t time.Time) *TimeSeries {
+	return ts.slice(ts.SearchTime(t), ts.Len)
+}
+
+//Creates a new time series of the points in [start, end)
+func (ts *TimeSeries) Between(start time.Time, end time.Time) *TimeSeries {
+	from, to := ts.SearchTime(start), ts.SearchTime(end)
+	if to < from {
+		to = from
+	}
+	return ts.slice(from, to)
+}
+
+//Creates a new time series of the points within d of the last point, inclusive
+func (ts *TimeSeries) Last(d time.Duration) *TimeSeries {
+	if ts.Len == 0 {
+		return NewTimeSeries()
+	}
+	return ts.From(ts.times[ts.Len-1].Add(-d))
+}
+
+func (ts *TimeSeries) slice(start int, end int) *TimeSeries {
+	sliced, _ := NewTimeSeriesFrom(ts.times[start:end], ts.series.y[start:end])
+	return sliced
+}
+
+//Applies a map function to each of the last numberOfPeriods periods of the series, and a reduce
+//function to the results, as Series.MapReduce.  Periods end at the last point, and each covers
+//[start, start + period).  Periods without points are skipped.
+func (ts *TimeSeries) MapReduce(mapFunction func(*TimeSeries) (time.Time, float64), reduceFunction func([]time.Time, []float64) *TimeSeries, period time.Duration, numberOfPeriods int) (*TimeSeries, error) {
+	if ts.Len == 0 {
+		return nil, ErrEmptySeries
+	}
+	if period <= 0 || numberOfPeriods < 1 {
+		return nil, ErrInvalidPeriod
+	}
+	last := ts.times[ts.Len-1]
+	start := last.Add(-period * time.Duration(numberOfPeriods))
+	var mappedt []time.Time
+	var mappedy []float64
+	for p := 0; p < numberOfPeriods; p++ {
+		end := start.Add(period)
+		//The final period includes the last point
+		if p == numberOfPeriods-1 {
+			end = last.Add(1)
+		}
+		window := ts.Between(start, end)
+		start = start.Add(period)
+		if window.Len == 0 {
+			continue
+		}
+		t, y := mapFunction(window)
+		mappedt = append(mappedt, t)
+		mappedy = append(mappedy, y)
+	}
+	return reduceFunction(mappedt, mappedy), nil
+}
+
+//Applies the aggregate function to the values in the window (t - window, t] ending at each point
+func (ts *TimeSeries) Rolling(window time.Duration, aggregate func(values []float64) float64) (*TimeSeries, error) {
+	if window <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	rolled := NewTimeSeries()
+	start := 0
+	for i, t := range ts.times {
+		for !ts.times[start].After(t.Add(-window)) {
+			start++
+		}
+		rolled.Add(t, aggregate(ts.series.y[start:i+1]))
+	}
+	return rolled, nil
+}
+
+//The mean of the values in the window (t - window, t] ending at each point
+func (ts *TimeSeries) RollingMean(window time.Duration) (*TimeSeries, error) {
+	return ts.Rolling(window, func(values []float64) float64 {
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	})
+}
+
+//Writes the series as lines of RFC 3339 timestamps with nanoseconds and values
+func (ts *TimeSeries) SavePlot(path string, name string) error {
+	lines := make([]string, ts.Len)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s %v", ts.times[i].Format(time.RFC3339Nano), ts.series.y[i])
+	}
+	return writeLines(lines, path+"/"+name)
+}
@@ -0,0 +1,97 @@
+package analytics
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeSeries(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 123456789, time.UTC)
+	times := make([]time.Time, 48)
+	values := make([]float64, 48)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Hour)
+		values[i] = float64(i)
+	}
+	ts, err := NewTimeSeriesFrom(times, values)
+	if err != nil {
+		t.Fatal("NewTimeSeriesFrom returned", err)
+	}
+
+	//Timestamps round-trip exactly
+	if got, _ := ts.Point(5); !got.Equal(times[5]) || got.Nanosecond() != 123456789 {
+		t.Error("Point 5 was at", got)
+	}
+	if x := TimeToX(times[1]); !XToTime(x).Round(time.Microsecond).Equal(times[1].Round(time.Microsecond)) {
+		t.Error("Time converted to", XToTime(x))
+	}
+
+	//Duration windows
+	if from := ts.From(times[40]); from.Len != 8 || from.Min != 40 {
+		t.Error("From returned", from.Len, "points from", from.Min)
+	}
+	if last := ts.Last(3 * time.Hour); last.Len != 4 {
+		t.Error("Last 3 hours had", last.Len, "points")
+	}
+	rolling, _ := ts.RollingMean(4 * time.Hour)
+	if _, y := rolling.Point(10); y != 8.5 {
+		t.Error("Rolling mean at 10 was", y)
+	}
+	daily, err := ts.MapReduce(func(day *TimeSeries) (time.Time, float64) {
+		first, _ := day.Point(0)
+		return first, day.Mean
+	}, func(t []time.Time, y []float64) *TimeSeries {
+		reduced, _ := NewTimeSeriesFrom(t, y)
+		return reduced
+	}, 24*time.Hour, 2)
+	//Periods end at the last point, so the first day runs from hour -1 to 23
+	if means := daily.Values(); err != nil || len(means) != 2 || means[0] != 11 || means[1] != 35 {
+		t.Error("Daily means were", daily.Values(), err)
+	}
+
+	//Fits use seconds since the epoch
+	fit := ts.Series().FitLinear()
+	if rate := fit.Model.(*LinearModel).Gradient * 3600; math.Abs(rate-1) > 1e-9 {
+		t.Error("Hourly rate was", rate)
+	}
+
+	dir, _ := ioutil.TempDir("", "timeseries")
+	defer os.RemoveAll(dir)
+	if err := ts.SavePlot(dir, "plot"); err != nil {
+		t.Fatal("SavePlot returned", err)
+	}
+	plot, _ := ioutil.ReadFile(dir + "/plot")
+	if !strings.HasPrefix(string(plot), "2024-03-01T00:00:00.123456789Z 0\n") {
+		t.Error("Plot started", string(plot[:40]))
+	}
+
+	//Series.SavePlot no longer truncates x to an integer
+	ts.Series().SavePlot(dir, "series")
+	plot, _ = ioutil.ReadFile(dir + "/series")
+	if !strings.HasPrefix(string(plot), "1709251200.123456") {
+		t.Error("Series plot started", string(plot[:30]))
+	}
+
+	if _, err := NewTimeSeriesFrom([]time.Time{times[1], times[0]}, []float64{1, 2}); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("Unordered times returned", err)
+	}
+	if _, err := ts.Rolling(0, nil); !errors.Is(err, ErrInvalidPeriod) {
+		t.Error("Rolling with no window returned", err)
+	}
+
+	//Out of order times are rejected rather than breaking the binary searches
+	added := NewTimeSeries()
+	added.Add(times[0], 1)
+	added.Add(times[2], 2)
+	if err := added.AddE(times[1], 3); !errors.Is(err, ErrNonMonotonicX) || added.Len != 2 {
+		t.Error("Adding an earlier time returned", err, "with", added.Len, "points")
+	}
+	if _, err := NewTimeSeriesFromSeries(NewSeriesFrom([]float64{2, 1}, []float64{1, 2})); !errors.Is(err, ErrNonMonotonicX) {
+		t.Error("Unordered series returned", err)
+	}
+}